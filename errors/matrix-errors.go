@@ -6,4 +6,7 @@ var (
 	ErrMatricesDimensionsMustMatch = errors.New("matrices dimensions must match")
 	ErrRowsMustEqualColumns        = errors.New("rows must equal columns")
 	ErrRowsColsMustEqual           = errors.New("rows and columns must be equal")
+	ErrUnknownBackend              = errors.New("unknown backend: register it with matrix.RegisterBackend first")
+	ErrColumnVectorMismatch        = errors.New("column vector must have a single column and as many rows as the matrix")
+	ErrDestinationDimensions       = errors.New("destination matrix must have the dimensions of the result")
 )
@@ -1,11 +1,42 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrEmptyInputOutput    = errors.New("empty input/output array")
-	ErrInputOutputMismatch = errors.New("input/output mismatch: number of input nodes must equal input array length")
-	ErrInputNodesMismatch  = errors.New("input nodes must match input array length")
-	ErrOutputNodesMismatch = errors.New("output nodes must match target array length")
-	ErrInputOutputNodes    = errors.New("input/output nodes mismatch: number of input nodes must equal input array length, and output nodes must equal target array length")
+	ErrEmptyInputOutput     = errors.New("empty input/output array")
+	ErrInputOutputMismatch  = errors.New("input/output mismatch: number of input nodes must equal input array length")
+	ErrInputNodesMismatch   = errors.New("input nodes must match input array length")
+	ErrOutputNodesMismatch  = errors.New("output nodes must match target array length")
+	ErrInputOutputNodes     = errors.New("input/output nodes mismatch: number of input nodes must equal input array length, and output nodes must equal target array length")
+	ErrInvalidLayerSizes    = errors.New("invalid layer sizes: at least an input and an output layer are required and every layer must have at least one node")
+	ErrUnknownMonitor       = errors.New("early stopping monitors a value that is not recorded")
+	ErrValidationSplit      = errors.New("validation split must leave at least one sample for training and one for validation")
+	ErrStopTraining         = errors.New("training stopped by callback")
+	ErrLayerOutOfRange      = errors.New("layer index out of range")
+	ErrClipping             = errors.New("gradient clipping thresholds must not be negative")
+	ErrGradientsMismatch    = errors.New("gradients do not match the parameters of the network")
+	ErrDropoutRate          = errors.New("dropout rate must be at least 0 and less than 1")
+	ErrUnknownActivation    = errors.New("unknown activation: register it with RegisterActivation before importing")
+	ErrUnknownOptimizer     = errors.New("unknown optimizer: register it with RegisterOptimizer before importing")
+	ErrUnknownNormalization = errors.New("unknown normalization")
+	ErrUnknownLoss          = errors.New("unknown loss: register it with RegisterLoss before importing")
+	ErrNoLayers             = errors.New("network has no layers")
+	ErrBatchNormBatchSize   = errors.New("networks with BatchNorm layers need at least two samples per batch")
 )
+
+// RowError reports the row of a batch that caused Err.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
@@ -1,35 +1,194 @@
 package neuraln
 
-import "neuraln/neural"
+import (
+	"context"
+	"neuraln/errors"
+	"neuraln/neural"
+	"sync"
+	"sync/atomic"
+)
 
+// NeuralNetwork is safe for concurrent use. Predict and Evaluate read an immutable
+// snapshot of the weights and never wait for training; training methods are
+// serialized and publish a new snapshot after every epoch and when they return.
+// The other methods wait for the end of the current epoch of a running training,
+// and they may also be called from its callbacks.
 type NeuralNetwork struct {
 	neural *neural.Neural
+	// training serializes the training methods.
+	training sync.Mutex
+	// mu serializes the methods that use the network. Training releases it between
+	// epochs and while the callbacks run.
+	mu sync.Mutex
+	// published is the snapshot read by Predict and Evaluate.
+	published atomic.Pointer[neural.Neural]
+}
+
+// wrap returns a NeuralNetwork around n with its current weights published.
+func wrap(n *neural.Neural) *NeuralNetwork {
+	nn := &NeuralNetwork{neural: n}
+	nn.publish()
+	return nn
+}
+
+// publish replaces the snapshot read by Predict and Evaluate with a copy of the
+// current weights.
+func (n *NeuralNetwork) publish() {
+	n.published.Store(n.neural.Snapshot())
+}
+
+// release runs call, if any, without holding mu, so that it and the methods waiting
+// for the network can use it. It asks the training of trained to stop once the
+// network has been replaced. The caller holds mu.
+func (n *NeuralNetwork) release(trained *neural.Neural, call func() error) (err error) {
+	n.mu.Unlock()
+	defer func() {
+		n.mu.Lock()
+		if err == nil && n.neural != trained {
+			err = errors.ErrStopTraining
+		}
+	}()
+
+	if call == nil {
+		return nil
+	}
+	return call()
+}
+
+// released returns callback with its functions run by release.
+func (n *NeuralNetwork) released(trained *neural.Neural, callback neural.Callback) neural.Callback {
+	var wrapped neural.Callback
+	if callback.OnBatchEnd != nil {
+		wrapped.OnBatchEnd = func(log neural.BatchLog) error {
+			return n.release(trained, func() error { return callback.OnBatchEnd(log) })
+		}
+	}
+	if callback.OnEpochEnd != nil {
+		wrapped.OnEpochEnd = func(log neural.EpochLog) error {
+			return n.release(trained, func() error { return callback.OnEpochEnd(log) })
+		}
+	}
+	return wrapped
 }
 
 func New(inputNodes, hiddenNodes, outputNodes int) *NeuralNetwork {
 	neural := neural.Neural{}
-	return &NeuralNetwork{
-		neural.Create(inputNodes, hiddenNodes, outputNodes),
+	return wrap(neural.Create(inputNodes, hiddenNodes, outputNodes))
+}
+
+// NewDeep creates a network with an arbitrary number of hidden layers. sizes lists
+// the width of every layer from input to output, e.g. []int{784, 256, 128, 10}.
+// Options such as neural.WithOutputActivation customize the layers.
+func NewDeep(sizes []int, options ...neural.Option) (*NeuralNetwork, error) {
+	n, err := (&neural.Neural{}).CreateDeep(sizes, options...)
+	if err != nil {
+		return nil, err
 	}
+	return wrap(n), nil
 }
 
 func ImportJSON(data []byte) (*neural.Neural, error) {
 	return neural.ImportJSON(data)
 }
 
+// ExportJSON exports the network including its optimizer state. During training it
+// exports the weights of the current epoch once it ends, so callbacks can save
+// checkpoints.
 func (n *NeuralNetwork) ExportJSON() ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	return n.neural.ExportJSON()
 }
 
+// LoadJSON replaces the network with one exported by ExportJSON, see Replace.
+func (n *NeuralNetwork) LoadJSON(data []byte) error {
+	imported, err := neural.ImportJSON(data)
+	if err != nil {
+		return err
+	}
+	n.Replace(imported)
+	return nil
+}
+
+// Replace makes network, e.g. one from ImportJSON, the network that is trained and
+// used for predictions from now on. A running training trains the replaced network,
+// so it stops at the end of its current epoch, or of the callback that called Replace.
+func (n *NeuralNetwork) Replace(network *neural.Neural) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.neural = network
+	n.publish()
+}
+
 func (n *NeuralNetwork) Train(inputArray, targetArray [][]float64, epochs int) error {
-	return n.neural.Train(inputArray, targetArray, epochs)
+	_, err := n.TrainContext(context.Background(), inputArray, targetArray, neural.TrainOptions{Epochs: epochs})
+	return err
+}
+
+// TrainWithOptions trains the network on mini-batches, see neural.TrainOptions, and
+// returns the loss and metrics of every epoch.
+func (n *NeuralNetwork) TrainWithOptions(inputArray, targetArray [][]float64, options neural.TrainOptions) (*neural.History, error) {
+	return n.TrainContext(context.Background(), inputArray, targetArray, options)
+}
+
+// TrainContext is TrainWithOptions that stops with ctx.Err() once ctx is done,
+// keeping the weights of the last completed training step.
+func (n *NeuralNetwork) TrainContext(ctx context.Context, inputArray, targetArray [][]float64, options neural.TrainOptions) (*neural.History, error) {
+	n.training.Lock()
+	defer n.training.Unlock()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	defer n.publish()
+
+	// Publish the weights of every epoch before the caller's callbacks see it, and
+	// let the methods waiting for the network use it between epochs
+	trained := n.neural
+	callbacks := make([]neural.Callback, 0, len(options.Callbacks)+1)
+	callbacks = append(callbacks, neural.Callback{OnEpochEnd: func(neural.EpochLog) error {
+		n.publish()
+		return n.release(trained, nil)
+	}})
+	for _, callback := range options.Callbacks {
+		callbacks = append(callbacks, n.released(trained, callback))
+	}
+	options.Callbacks = callbacks
+
+	return trained.TrainContext(ctx, inputArray, targetArray, options)
+}
+
+// Gradients computes the gradients of the loss for the given samples without
+// updating the network, see neural.Neural.Gradients.
+func (n *NeuralNetwork) Gradients(inputArray, targetArray [][]float64) (*neural.Gradients, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.neural.Gradients(inputArray, targetArray)
+}
+
+// ApplyGradients updates the network with the given gradients. A nil optimizer
+// uses the network's own.
+func (n *NeuralNetwork) ApplyGradients(grads *neural.Gradients, optimizer neural.Optimizer) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	defer n.publish()
+	return n.neural.ApplyGradients(grads, optimizer)
+}
+
+// Evaluate returns the loss of the network averaged over the given samples.
+func (n *NeuralNetwork) Evaluate(inputArray, targetArray [][]float64) (float64, error) {
+	return n.published.Load().Evaluate(inputArray, targetArray)
 }
 
 func (n *NeuralNetwork) Predict(inputArray []float64) ([]float64, error) {
-	predictions, err := n.neural.FeedForword(inputArray)
+	predictions, err := n.published.Load().FeedForword(inputArray)
 	if err != nil {
 		return nil, err
 	}
 
 	return predictions.Flatten(), nil
 }
+
+// PredictBatch computes the outputs of many samples at once, see
+// neural.Neural.PredictBatch.
+func (n *NeuralNetwork) PredictBatch(inputArray [][]float64) ([][]float64, error) {
+	return n.published.Load().PredictBatch(inputArray)
+}
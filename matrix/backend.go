@@ -0,0 +1,184 @@
+package matrix
+
+import (
+	"neuraln/errors"
+	"sort"
+	"sync"
+)
+
+// Backend performs the compute-heavy operations of matrices. Every operation but
+// Gemm returns a new dense Matrix and leaves its operands unchanged. The pure-Go "cpu"
+// backend is always available; builds with the cuda tag also register "cuda".
+type Backend interface {
+	Name() string
+	// Add returns a + b.
+	Add(a, b *Matrix) (*Matrix, error)
+	// Sub returns a - b.
+	Sub(a, b *Matrix) (*Matrix, error)
+	// Mul returns the matrix product of a and b.
+	Mul(a, b *Matrix) (*Matrix, error)
+	// Gemm computes c = alpha*op(a)*op(b) + beta*c in place, where op transposes
+	// its operand when the matching flag is set, see the Gemm function.
+	Gemm(transA, transB bool, alpha float64, a, b *Matrix, beta float64, c *Matrix) error
+	// Hadamard returns the element-wise product of a and b.
+	Hadamard(a, b *Matrix) (*Matrix, error)
+	// Transpose returns the transpose of a.
+	Transpose(a *Matrix) *Matrix
+	// ScalarMul returns a multiplied by n.
+	ScalarMul(a *Matrix, n float64) *Matrix
+	// Sigmoid applies the sigmoid function to every element of a.
+	Sigmoid(a *Matrix) *Matrix
+	// DSigmoid returns a * (1 - a) for every element of a, the derivative of the
+	// sigmoid function in terms of its output.
+	DSigmoid(a *Matrix) *Matrix
+	// Randomize returns a Matrix of the shape of a with values drawn uniformly
+	// from [-1, 1).
+	Randomize(a *Matrix) *Matrix
+}
+
+var backends = struct {
+	sync.RWMutex
+	registered map[string]Backend
+	current    Backend
+}{registered: map[string]Backend{}}
+
+func init() {
+	RegisterBackend(cpuBackend{})
+	backends.current = cpuBackend{}
+}
+
+// RegisterBackend makes backend available to GetBackend and SetDefaultBackend
+// under its name, replacing any backend registered under the same name.
+func RegisterBackend(backend Backend) {
+	backends.Lock()
+	defer backends.Unlock()
+	backends.registered[backend.Name()] = backend
+}
+
+// GetBackend returns the backend registered under name.
+func GetBackend(name string) (Backend, error) {
+	backends.RLock()
+	defer backends.RUnlock()
+	backend, ok := backends.registered[name]
+	if !ok {
+		return nil, errors.ErrUnknownBackend
+	}
+	return backend, nil
+}
+
+// Backends returns the names of all registered backends in alphabetical order.
+func Backends() []string {
+	backends.RLock()
+	defer backends.RUnlock()
+	names := make([]string, 0, len(backends.registered))
+	for name := range backends.registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDefaultBackend selects the backend used by the methods of Matrix for the
+// whole process.
+func SetDefaultBackend(name string) error {
+	backend, err := GetBackend(name)
+	if err != nil {
+		return err
+	}
+
+	backends.Lock()
+	defer backends.Unlock()
+	backends.current = backend
+	return nil
+}
+
+// DefaultBackend returns the backend used by the methods of Matrix.
+func DefaultBackend() Backend {
+	backends.RLock()
+	defer backends.RUnlock()
+	return backends.current
+}
+
+// AddFromMatrix adds another Matrix to the current Matrix using the default backend.
+func (m *Matrix) AddFromMatrix(sMatrix *Matrix) (*Matrix, error) {
+	return DefaultBackend().Add(m, sMatrix)
+}
+
+// SubtractMatrix subtracts another Matrix from the current Matrix using the default backend.
+func (m *Matrix) SubtractMatrix(sMatrix *Matrix) (*Matrix, error) {
+	return DefaultBackend().Sub(m, sMatrix)
+}
+
+// DotProduct performs matrix multiplication using the default backend.
+func (m *Matrix) DotProduct(sMatrix *Matrix) (*Matrix, error) {
+	return DefaultBackend().Mul(m, sMatrix)
+}
+
+// Gemm computes c = alpha*op(a)*op(b) + beta*c in place using the default backend,
+// like the BLAS routine of the same name. op(x) is the transpose of x when the
+// matching flag is set and x itself otherwise; the transposes are never
+// materialized. c is written through its own layout, so it may be a view, but it
+// must not share storage with a or b. When beta is 0 the previous contents of c are ignored, so c need not be initialized.
+//
+// Parameters:
+//   - transA, transB: Whether to use the transpose of a and b.
+//   - alpha: The factor of the product.
+//   - a, b: The operands of the product; op(a) must have as many columns as op(b)
+//     has rows.
+//   - beta: The factor of the previous contents of c.
+//   - c: The result, with as many rows as op(a) and as many columns as op(b).
+//
+// Returns:
+//   - error: ErrRowsMustEqualColumns if the product is undefined,
+//     ErrMatricesDimensionsMustMatch if c has the wrong shape, otherwise nil.
+func Gemm(transA, transB bool, alpha float64, a, b *Matrix, beta float64, c *Matrix) error {
+	return DefaultBackend().Gemm(transA, transB, alpha, a, b, beta, c)
+}
+
+// gemmOperands returns op(a) and op(b) as views and checks that their product
+// fits into c.
+func gemmOperands(transA, transB bool, a, b, c *Matrix) (*Matrix, *Matrix, error) {
+	if transA {
+		a = a.T()
+	}
+	if transB {
+		b = b.T()
+	}
+	if a.Col != b.Row {
+		return nil, nil, errors.ErrRowsMustEqualColumns
+	}
+	if c.Row != a.Row || c.Col != b.Col {
+		return nil, nil, errors.ErrMatricesDimensionsMustMatch
+	}
+	return a, b, nil
+}
+
+// HadProduct performs element-wise multiplication (Hadamard product) and returns a new Matrix.
+func (m *Matrix) HadProduct(sMatrix *Matrix) (*Matrix, error) {
+	return DefaultBackend().Hadamard(m, sMatrix)
+}
+
+// Randomize fills a new Matrix with random values between -1 and 1.
+func (m *Matrix) Randomize() *Matrix {
+	return DefaultBackend().Randomize(m)
+}
+
+// Transpose returns the transpose of the Matrix as a dense copy, see T for a view.
+func (m *Matrix) Transpose() *Matrix {
+	return DefaultBackend().Transpose(m)
+}
+
+// ScalerMul multiplies each element of the Matrix by a scalar and returns a new Matrix.
+func (m *Matrix) ScalerMul(n float64) *Matrix {
+	return DefaultBackend().ScalarMul(m, n)
+}
+
+// Sigmoid applies the sigmoid function to each element of the Matrix and returns a new Matrix.
+func (m *Matrix) Sigmoid() *Matrix {
+	return DefaultBackend().Sigmoid(m)
+}
+
+// DSigmoid applies the derivative of the sigmoid function to each element of the Matrix and returns a new Matrix.
+func (m *Matrix) DSigmoid() *Matrix {
+	return DefaultBackend().DSigmoid(m)
+}
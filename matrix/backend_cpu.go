@@ -0,0 +1,101 @@
+package matrix
+
+import (
+	"math/rand/v2"
+	"neuraln/errors"
+)
+
+// cpuBackend is the pure-Go backend, registered as "cpu". It accepts operands of
+// any layout, including views.
+type cpuBackend struct{}
+
+func (cpuBackend) Name() string { return "cpu" }
+
+// Add adds two matrices element by element.
+func (cpuBackend) Add(a, b *Matrix) (*Matrix, error) {
+	if a.Col != b.Col || a.Row != b.Row {
+		return nil, errors.ErrMatricesDimensionsMustMatch
+	}
+	return zip(a, b, func(x, y float64) float64 { return x + y }), nil
+}
+
+// Sub subtracts b from a element by element.
+func (cpuBackend) Sub(a, b *Matrix) (*Matrix, error) {
+	if a.Col != b.Col || a.Row != b.Row {
+		return nil, errors.ErrMatricesDimensionsMustMatch
+	}
+	return zip(a, b, func(x, y float64) float64 { return x - y }), nil
+}
+
+// Mul performs matrix multiplication with a cache-blocked loop that large products
+// spread over up to GOMAXPROCS goroutines, see gemm.
+func (cpuBackend) Mul(a, b *Matrix) (*Matrix, error) {
+	if a.Col != b.Row {
+		return nil, errors.ErrRowsMustEqualColumns
+	}
+
+	result := New(a.Row, b.Col)
+	gemm(1, a, b, 0, result)
+	return result, nil
+}
+
+// Gemm computes c = alpha*op(a)*op(b) + beta*c in place. The transposes are views,
+// so only operands whose layout neither kernel of gemm can walk are copied.
+func (cpuBackend) Gemm(transA, transB bool, alpha float64, a, b *Matrix, beta float64, c *Matrix) error {
+	a, b, err := gemmOperands(transA, transB, a, b, c)
+	if err != nil {
+		return err
+	}
+	gemm(alpha, a, b, beta, c)
+	return nil
+}
+
+// Hadamard performs element-wise multiplication.
+func (cpuBackend) Hadamard(a, b *Matrix) (*Matrix, error) {
+	if a.Row != b.Row || a.Col != b.Col {
+		return nil, errors.ErrRowsColsMustEqual
+	}
+	return zip(a, b, func(x, y float64) float64 { return x * y }), nil
+}
+
+// HadamardInto stores the element-wise product of a and b in dst, which may be a.
+func (cpuBackend) HadamardInto(dst, a, b *Matrix) error {
+	return a.HadProductInto(dst, b)
+}
+
+// Transpose copies the transposed view of a into a dense Matrix.
+func (cpuBackend) Transpose(a *Matrix) *Matrix {
+	return a.T().Copy()
+}
+
+// ScalarMul multiplies each element by n.
+func (cpuBackend) ScalarMul(a *Matrix, n float64) *Matrix {
+	return a.Map(func(v float64) float64 { return v * n })
+}
+
+// Sigmoid applies the sigmoid function to each element.
+func (cpuBackend) Sigmoid(a *Matrix) *Matrix {
+	return a.Map(sigmoid)
+}
+
+// DSigmoid applies the derivative of the sigmoid function to each element.
+func (cpuBackend) DSigmoid(a *Matrix) *Matrix {
+	return a.Map(dSigmoid)
+}
+
+// Randomize fills a new Matrix with random values between -1 and 1 drawn from the
+// global source.
+func (cpuBackend) Randomize(a *Matrix) *Matrix {
+	result := New(a.Row, a.Col)
+	for i := range result.Data {
+		result.Data[i] = rand.Float64()*(1-(-1)) - 1
+	}
+	return result
+}
+
+// zip combines the elements of a and b, which must have the same shape, with f.
+func zip(a, b *Matrix, f func(x, y float64) float64) *Matrix {
+	result := New(a.Row, a.Col)
+	zipInto(result, a, b, f)
+	return result
+}
@@ -0,0 +1,283 @@
+//go:build cuda
+
+package matrix
+
+/*
+#cgo LDFLAGS: -L../build/lib -lmatrix_ops
+#include "cuda/matrix_ops.h"
+*/
+import "C"
+
+import (
+	"neuraln/errors"
+	"unsafe"
+)
+
+// cudaBackend runs the operations on the GPU through the CUDA library in
+// matrix/cuda, registered as "cuda". Builds with the cuda tag select it as the
+// default backend; SetDefaultBackend("cpu") switches back to the CPU.
+type cudaBackend struct{}
+
+func init() {
+	RegisterBackend(cudaBackend{})
+	backends.current = cudaBackend{}
+}
+
+func (cudaBackend) Name() string { return "cuda" }
+
+// Add adds two matrices element by element using CUDA.
+func (cudaBackend) Add(m, sMatrix *Matrix) (*Matrix, error) {
+	if m.Col != sMatrix.Col || m.Row != sMatrix.Row {
+		return nil, errors.ErrMatricesDimensionsMustMatch
+	}
+
+	result := New(m.Row, m.Col)
+
+	// Dense row-major views of the operands; the result is written in place
+	a := m.dense()
+	b := sMatrix.dense()
+	c := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixAdd(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		(*C.double)(unsafe.Pointer(&c[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result, nil
+}
+
+// Sub subtracts sMatrix from m element by element using CUDA.
+func (cudaBackend) Sub(m, sMatrix *Matrix) (*Matrix, error) {
+	if m.Col != sMatrix.Col || m.Row != sMatrix.Row {
+		return nil, errors.ErrMatricesDimensionsMustMatch
+	}
+
+	result := New(m.Row, m.Col)
+
+	// Dense row-major views of the operands; the result is written in place
+	a := m.dense()
+	b := sMatrix.dense()
+	c := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixSub(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		(*C.double)(unsafe.Pointer(&c[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result, nil
+}
+
+// Mul performs matrix multiplication using CUDA.
+func (cudaBackend) Mul(m, sMatrix *Matrix) (*Matrix, error) {
+	if m.Col != sMatrix.Row {
+		return nil, errors.ErrRowsMustEqualColumns
+	}
+
+	result := New(m.Row, sMatrix.Col)
+
+	// Dense row-major views of the operands; the result is written in place
+	a := m.dense()
+	b := sMatrix.dense()
+	c := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixMul(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		(*C.double)(unsafe.Pointer(&c[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+		C.int(sMatrix.Col),
+	)
+
+	return result, nil
+}
+
+// Gemm computes c = alpha*op(a)*op(b) + beta*c using CUDA. The kernel reads the
+// operands transposed in place, so transposes, including operands that are
+// transposed views of dense matrices, are never copied.
+func (cudaBackend) Gemm(transA, transB bool, alpha float64, m, sMatrix *Matrix, beta float64, result *Matrix) error {
+	opA, _, err := gemmOperands(transA, transB, m, sMatrix, result)
+	if err != nil {
+		return err
+	}
+
+	// Dense row-major storage of the operands and of the result, which is written
+	// in place when it is dense and copied back otherwise
+	a, transA := cudaOperand(m, transA)
+	b, transB := cudaOperand(sMatrix, transB)
+	out := result.Contiguous()
+	c := out.dense()
+	if len(a) == 0 || len(b) == 0 || len(c) == 0 {
+		// The kernel needs storage to point to; an empty product only scales c
+		scaleRows(out, beta)
+	} else {
+		C.cudaMatrixGemm(
+			(*C.double)(unsafe.Pointer(&a[0])),
+			(*C.double)(unsafe.Pointer(&b[0])),
+			(*C.double)(unsafe.Pointer(&c[0])),
+			C.int(result.Row),
+			C.int(opA.Col),
+			C.int(result.Col),
+			cudaFlag(transA),
+			cudaFlag(transB),
+			C.double(alpha),
+			C.double(beta),
+		)
+	}
+
+	if out != result {
+		for i := 0; i < result.Row; i++ {
+			for j := 0; j < result.Col; j++ {
+				result.Set(i, j, out.Data[out.index(i, j)])
+			}
+		}
+	}
+	return nil
+}
+
+// cudaOperand returns the dense row-major storage of m for the Gemm kernel and
+// whether the kernel reads it transposed. A transposed view of a dense matrix is
+// passed as its dense original with the flag flipped instead of being copied.
+func cudaOperand(m *Matrix, trans bool) ([]float64, bool) {
+	m.sync()
+	if !m.IsDense() && m.T().IsDense() {
+		return m.T().dense(), !trans
+	}
+	return m.dense(), trans
+}
+
+// cudaFlag converts a transpose flag for the CUDA library.
+func cudaFlag(flag bool) C.int {
+	if flag {
+		return 1
+	}
+	return 0
+}
+
+// Hadamard performs element-wise multiplication using CUDA.
+func (cudaBackend) Hadamard(m, sMatrix *Matrix) (*Matrix, error) {
+	if m.Row != sMatrix.Row || m.Col != sMatrix.Col {
+		return nil, errors.ErrRowsColsMustEqual
+	}
+
+	result := New(m.Row, m.Col)
+
+	// Dense row-major views of the operands; the result is written in place
+	a := m.dense()
+	b := sMatrix.dense()
+	c := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixHadamard(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		(*C.double)(unsafe.Pointer(&c[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result, nil
+}
+
+// Randomize fills a new Matrix with random values using CUDA.
+func (cudaBackend) Randomize(m *Matrix) *Matrix {
+	result := New(m.Row, m.Col)
+
+	// The random values are written into the result in place
+	a := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixRand(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result
+}
+
+// Transpose returns the transpose of the Matrix using CUDA.
+func (cudaBackend) Transpose(m *Matrix) *Matrix {
+	result := New(m.Col, m.Row)
+
+	// Dense row-major view of the operand; the result is written in place
+	a := m.dense()
+	b := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixTranspose(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result
+}
+
+// ScalarMul multiplies the Matrix by a scalar value using CUDA.
+func (cudaBackend) ScalarMul(m *Matrix, n float64) *Matrix {
+	result := New(m.Row, m.Col)
+
+	// Dense row-major view of the operand; the result is written in place
+	a := m.dense()
+	b := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixScalarMul(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		C.double(n),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result
+}
+
+// Sigmoid applies the sigmoid function to the Matrix using CUDA.
+func (cudaBackend) Sigmoid(m *Matrix) *Matrix {
+	result := New(m.Row, m.Col)
+
+	// Dense row-major view of the operand; the result is written in place
+	a := m.dense()
+	b := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixSigmoid(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result
+}
+
+// DSigmoid applies the derivative of the sigmoid function to the Matrix using CUDA.
+func (cudaBackend) DSigmoid(m *Matrix) *Matrix {
+	result := New(m.Row, m.Col)
+
+	// Dense row-major view of the operand; the result is written in place
+	a := m.dense()
+	b := result.Data
+
+	// Call CUDA wrapper
+	C.cudaMatrixDSigmoid(
+		(*C.double)(unsafe.Pointer(&a[0])),
+		(*C.double)(unsafe.Pointer(&b[0])),
+		C.int(m.Row),
+		C.int(m.Col),
+	)
+
+	return result
+}
@@ -223,6 +223,68 @@ void cudaMatrixMul(double* A, double* B, double* C, int rowsA, int colsA, int co
     cudaFree(d_C);
 }
 
+// Wrapper for C = alpha * op(A) * op(B) + beta * C
+void cudaMatrixGemm(double* A, double* B, double* C, int rowsC, int inner, int colsC, int transA, int transB, double alpha, double beta) {
+    double *d_A, *d_B, *d_C;
+    size_t sizeA = rowsC * inner * sizeof(double);
+    size_t sizeB = inner * colsC * sizeof(double);
+    size_t sizeC = rowsC * colsC * sizeof(double);
+
+    #ifdef DEBUG
+    printf("Allocating memory for matrixGemm: sizeA = %zu, sizeB = %zu, sizeC = %zu\n", sizeA, sizeB, sizeC);
+    #endif
+
+    // Allocate device memory
+    cudaError_t err = cudaMalloc((void**)&d_A, sizeA);
+    if (err != cudaSuccess) {
+        fprintf(stderr, "cudaMalloc failed for d_A: %s\n", cudaGetErrorString(err));
+        return;
+    }
+    err = cudaMalloc((void**)&d_B, sizeB);
+    if (err != cudaSuccess) {
+        fprintf(stderr, "cudaMalloc failed for d_B: %s\n", cudaGetErrorString(err));
+        cudaFree(d_A);
+        return;
+    }
+    err = cudaMalloc((void**)&d_C, sizeC);
+    if (err != cudaSuccess) {
+        fprintf(stderr, "cudaMalloc failed for d_C: %s\n", cudaGetErrorString(err));
+        cudaFree(d_A);
+        cudaFree(d_B);
+        return;
+    }
+
+    // Copy data to device; C is only read when beta is not 0
+    err = cudaMemcpy(d_A, A, sizeA, cudaMemcpyHostToDevice);
+    if (err == cudaSuccess) {
+        err = cudaMemcpy(d_B, B, sizeB, cudaMemcpyHostToDevice);
+    }
+    if (err == cudaSuccess && beta != 0.0) {
+        err = cudaMemcpy(d_C, C, sizeC, cudaMemcpyHostToDevice);
+    }
+    if (err != cudaSuccess) {
+        fprintf(stderr, "cudaMemcpy failed for matrixGemm operands: %s\n", cudaGetErrorString(err));
+        cudaFree(d_A);
+        cudaFree(d_B);
+        cudaFree(d_C);
+        return;
+    }
+
+    // Call CUDA kernel launch wrapper
+    launchMatrixGemm(d_A, d_B, d_C, rowsC, inner, colsC, transA, transB, alpha, beta);
+
+    // Copy result back to host
+    err = cudaMemcpy(C, d_C, sizeC, cudaMemcpyDeviceToHost);
+    if (err != cudaSuccess) {
+        fprintf(stderr, "cudaMemcpy failed for d_C: %s\n", cudaGetErrorString(err));
+    }
+
+    // Free device memory
+    cudaFree(d_A);
+    cudaFree(d_B);
+    cudaFree(d_C);
+}
+
 // Wrapper for element-wise matrix multiplication (Hadamard product)
 void cudaMatrixHadamard(double* A, double* B, double* C, int rows, int cols) {
     double *d_A, *d_B, *d_C;
@@ -79,6 +79,57 @@ __global__ void matrixMul(const double* __restrict__ A, const double* __restrict
     }
 }
 
+// CUDA kernel for C = alpha * op(A) * op(B) + beta * C, where op transposes its
+// operand when the matching flag is set. A and B are stored row-major with the
+// shapes they have before op is applied.
+__global__ void matrixGemm(const double* __restrict__ A, const double* __restrict__ B, double* __restrict__ C, int rowsC, int inner, int colsC, int transA, int transB, double alpha, double beta) {
+    // Tile size
+    const int TILE_SIZE = 16;
+
+    // Shared memory for tiles of op(A) and op(B)
+    __shared__ double sharedA[TILE_SIZE][TILE_SIZE];
+    __shared__ double sharedB[TILE_SIZE][TILE_SIZE];
+
+    // Thread indices
+    int row = blockIdx.y * TILE_SIZE + threadIdx.y;
+    int col = blockIdx.x * TILE_SIZE + threadIdx.x;
+
+    double sum = 0.0;
+
+    // Loop over tiles
+    for (int t = 0; t < (inner + TILE_SIZE - 1) / TILE_SIZE; t++) {
+        // Load tiles into shared memory, reading A and B transposed if requested
+        int k = t * TILE_SIZE + threadIdx.x;
+        if (row < rowsC && k < inner) {
+            sharedA[threadIdx.y][threadIdx.x] = transA ? A[k * rowsC + row] : A[row * inner + k];
+        } else {
+            sharedA[threadIdx.y][threadIdx.x] = 0.0;
+        }
+
+        k = t * TILE_SIZE + threadIdx.y;
+        if (col < colsC && k < inner) {
+            sharedB[threadIdx.y][threadIdx.x] = transB ? B[col * inner + k] : B[k * colsC + col];
+        } else {
+            sharedB[threadIdx.y][threadIdx.x] = 0.0;
+        }
+
+        __syncthreads(); // Synchronize to ensure all threads have loaded their tiles
+
+        // Compute partial sum for the tile
+        for (int i = 0; i < TILE_SIZE; i++) {
+            sum += sharedA[threadIdx.y][i] * sharedB[i][threadIdx.x];
+        }
+
+        __syncthreads(); // Synchronize before loading the next tile
+    }
+
+    // Write the result to global memory; a beta of 0 ignores the previous contents
+    if (row < rowsC && col < colsC) {
+        double previous = beta == 0.0 ? 0.0 : beta * C[row * colsC + col];
+        C[row * colsC + col] = alpha * sum + previous;
+    }
+}
+
 // CUDA kernel for matrix Hadamard product
 __global__ void matrixHadamard(const double* __restrict__ A, const double* __restrict__ B, double* __restrict__ C, int rows, int cols) {
     int idx = blockIdx.x * blockDim.x + threadIdx.x;
@@ -247,6 +298,35 @@ extern "C" void launchMatrixMul(double* d_A, double* d_B, double* d_C, int rowsA
     }
 }
 
+// Wrapper function for launching matrixGemm kernel
+extern "C" void launchMatrixGemm(double* d_A, double* d_B, double* d_C, int rowsC, int inner, int colsC, int transA, int transB, double alpha, double beta) {
+    dim3 threadsPerBlock(16, 16); // Optimal for shared memory tiles
+    dim3 blocksPerGrid((colsC + threadsPerBlock.x - 1) / threadsPerBlock.x,
+                       (rowsC + threadsPerBlock.y - 1) / threadsPerBlock.y);
+
+    #ifdef DEBUG
+    printf("Launching matrixGemm kernel: blocksPerGrid = (%d, %d), threadsPerBlock = (%d, %d)\n",
+           blocksPerGrid.x, blocksPerGrid.y, threadsPerBlock.x, threadsPerBlock.y);
+    #endif
+
+    // Launch the kernel
+    matrixGemm<<<blocksPerGrid, threadsPerBlock>>>(d_A, d_B, d_C, rowsC, inner, colsC, transA, transB, alpha, beta);
+
+    // Check for kernel launch errors
+    cudaError_t err = cudaGetLastError();
+    if (err != cudaSuccess) {
+        fprintf(stderr, "Kernel launch failed: %s\n", cudaGetErrorString(err));
+        return;
+    }
+
+    // Synchronize to ensure the kernel completes
+    err = cudaDeviceSynchronize();
+    if (err != cudaSuccess) {
+        fprintf(stderr, "Kernel execution failed: %s\n", cudaGetErrorString(err));
+        return;
+    }
+}
+
 // Wrapper function for launching matrixHadamard kernel
 extern "C" void launchMatrixHadamard(double* d_A, double* d_B, double* d_C, int rows, int cols) {
     int threadsPerBlock = 256;
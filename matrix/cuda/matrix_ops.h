@@ -10,6 +10,7 @@ void cudaMatrixRand(double* A, int rows, int cols);
 void cudaMatrixAdd(double* d_A, double* d_B, double* d_C, int rows, int cols);
 void cudaMatrixSub(double* d_A, double* d_B, double* d_C, int rows, int cols);
 void cudaMatrixMul(double* d_A, double* d_B, double* d_C, int rowsA, int colsA, int colsB);
+void cudaMatrixGemm(double* A, double* B, double* C, int rowsC, int inner, int colsC, int transA, int transB, double alpha, double beta);
 void cudaMatrixHadamard(double* A, double* B, double* C, int rows, int cols);
 void cudaMatrixTranspose(double* A, double* C, int rows, int cols);
 void cudaMatrixScalarMul(double* A,  double* C, double scalar, int rows, int cols);
@@ -0,0 +1,217 @@
+package matrix
+
+import (
+	"math"
+	"neuraln/errors"
+)
+
+// The Into variants of the operations write their result into dst, a Matrix of the
+// result's shape, instead of allocating a new one, so loops that run the same
+// operations over and over can reuse their buffers. dst is written through its own
+// layout and may be a view. For element-wise operations dst may be m or one of the
+// operands, which is what the InPlace variants do; otherwise it must not share
+// storage with them.
+//
+// Apart from DotProductInto, which runs Gemm on the default backend, they run on
+// the CPU: moving the operands to a device and back would cost more than the
+// element-wise work itself.
+
+// checkDestination returns ErrDestinationDimensions unless dst has the given shape.
+func checkDestination(dst *Matrix, rows, cols int) error {
+	if dst.Row != rows || dst.Col != cols {
+		return errors.ErrDestinationDimensions
+	}
+	return nil
+}
+
+// AddInto stores m + sMatrix in dst.
+func (m *Matrix) AddInto(dst, sMatrix *Matrix) error {
+	if m.Row != sMatrix.Row || m.Col != sMatrix.Col {
+		return errors.ErrMatricesDimensionsMustMatch
+	}
+	if err := checkDestination(dst, m.Row, m.Col); err != nil {
+		return err
+	}
+	zipInto(dst, m, sMatrix, func(x, y float64) float64 { return x + y })
+	return nil
+}
+
+// AddInPlace adds sMatrix to m.
+func (m *Matrix) AddInPlace(sMatrix *Matrix) error {
+	return m.AddInto(m, sMatrix)
+}
+
+// SubtractInto stores m - sMatrix in dst.
+func (m *Matrix) SubtractInto(dst, sMatrix *Matrix) error {
+	if m.Row != sMatrix.Row || m.Col != sMatrix.Col {
+		return errors.ErrMatricesDimensionsMustMatch
+	}
+	if err := checkDestination(dst, m.Row, m.Col); err != nil {
+		return err
+	}
+	zipInto(dst, m, sMatrix, func(x, y float64) float64 { return x - y })
+	return nil
+}
+
+// SubtractInPlace subtracts sMatrix from m.
+func (m *Matrix) SubtractInPlace(sMatrix *Matrix) error {
+	return m.SubtractInto(m, sMatrix)
+}
+
+// HadProductInto stores the element-wise product of m and sMatrix in dst.
+func (m *Matrix) HadProductInto(dst, sMatrix *Matrix) error {
+	if m.Row != sMatrix.Row || m.Col != sMatrix.Col {
+		return errors.ErrRowsColsMustEqual
+	}
+	if err := checkDestination(dst, m.Row, m.Col); err != nil {
+		return err
+	}
+	zipInto(dst, m, sMatrix, func(x, y float64) float64 { return x * y })
+	return nil
+}
+
+// HadProductInPlace multiplies m element-wise by sMatrix.
+func (m *Matrix) HadProductInPlace(sMatrix *Matrix) error {
+	return m.HadProductInto(m, sMatrix)
+}
+
+// DotProductInto stores the matrix product of m and sMatrix in dst, see Gemm.
+func (m *Matrix) DotProductInto(dst, sMatrix *Matrix) error {
+	if m.Col != sMatrix.Row {
+		return errors.ErrRowsMustEqualColumns
+	}
+	if err := checkDestination(dst, m.Row, sMatrix.Col); err != nil {
+		return err
+	}
+	return Gemm(false, false, 1, m, sMatrix, 0, dst)
+}
+
+// MapInto stores f applied to every element of m in dst.
+func (m *Matrix) MapInto(dst *Matrix, f func(float64) float64) error {
+	if err := checkDestination(dst, m.Row, m.Col); err != nil {
+		return err
+	}
+	mapInto(dst, m, f)
+	return nil
+}
+
+// MapInPlace replaces every element of m with f applied to it.
+func (m *Matrix) MapInPlace(f func(float64) float64) {
+	mapInto(m, m, f)
+}
+
+// ScalerMulInto stores m multiplied by n in dst.
+func (m *Matrix) ScalerMulInto(dst *Matrix, n float64) error {
+	return m.MapInto(dst, func(v float64) float64 { return v * n })
+}
+
+// ScalerMulInPlace multiplies every element of m by n.
+func (m *Matrix) ScalerMulInPlace(n float64) {
+	m.MapInPlace(func(v float64) float64 { return v * n })
+}
+
+// SigmoidInto stores the sigmoid of every element of m in dst.
+func (m *Matrix) SigmoidInto(dst *Matrix) error {
+	return m.MapInto(dst, sigmoid)
+}
+
+// SigmoidInPlace applies the sigmoid function to every element of m.
+func (m *Matrix) SigmoidInPlace() {
+	m.MapInPlace(sigmoid)
+}
+
+// DSigmoidInto stores v * (1 - v) for every element v of m in dst.
+func (m *Matrix) DSigmoidInto(dst *Matrix) error {
+	return m.MapInto(dst, dSigmoid)
+}
+
+// DSigmoidInPlace replaces every element v of m with v * (1 - v).
+func (m *Matrix) DSigmoidInPlace() {
+	m.MapInPlace(dSigmoid)
+}
+
+// TransposeInto stores the transpose of m in dst, which must not share storage
+// with m.
+func (m *Matrix) TransposeInto(dst *Matrix) error {
+	return m.T().CopyInto(dst)
+}
+
+// CopyInto copies the elements of m into dst.
+func (m *Matrix) CopyInto(dst *Matrix) error {
+	return m.MapInto(dst, func(v float64) float64 { return v })
+}
+
+// BroadcastAddInto stores m with the column vector column added to every column in
+// dst.
+func (m *Matrix) BroadcastAddInto(dst, column *Matrix) error {
+	if column.Col != 1 || column.Row != m.Row {
+		return errors.ErrColumnVectorMismatch
+	}
+	if err := checkDestination(dst, m.Row, m.Col); err != nil {
+		return err
+	}
+
+	m.sync()
+	column.sync()
+	dst.sync()
+	for i := 0; i < m.Row; i++ {
+		v := column.Data[column.index(i, 0)]
+		for j := 0; j < m.Col; j++ {
+			dst.Data[dst.index(i, j)] = m.Data[m.index(i, j)] + v
+		}
+	}
+	return nil
+}
+
+// BroadcastAddInPlace adds the column vector column to every column of m.
+func (m *Matrix) BroadcastAddInPlace(column *Matrix) error {
+	return m.BroadcastAddInto(m, column)
+}
+
+// SumColumnsInto stores the sum of the columns of m in dst, a column vector with
+// as many rows as m.
+func (m *Matrix) SumColumnsInto(dst *Matrix) error {
+	if err := checkDestination(dst, m.Row, 1); err != nil {
+		return err
+	}
+
+	m.sync()
+	dst.sync()
+	for i := 0; i < m.Row; i++ {
+		sum := 0.0
+		for j := 0; j < m.Col; j++ {
+			sum += m.Data[m.index(i, j)]
+		}
+		dst.Data[dst.index(i, 0)] = sum
+	}
+	return nil
+}
+
+// zipInto stores f applied to the elements of a and b, which have the shape of dst,
+// in dst.
+func zipInto(dst, a, b *Matrix, f func(x, y float64) float64) {
+	a.sync()
+	b.sync()
+	dst.sync()
+	for i := 0; i < dst.Row; i++ {
+		for j := 0; j < dst.Col; j++ {
+			dst.Data[dst.index(i, j)] = f(a.Data[a.index(i, j)], b.Data[b.index(i, j)])
+		}
+	}
+}
+
+// mapInto stores f applied to the elements of m, which has the shape of dst, in
+// dst.
+func mapInto(dst, m *Matrix, f func(float64) float64) {
+	m.sync()
+	dst.sync()
+	for i := 0; i < dst.Row; i++ {
+		for j := 0; j < dst.Col; j++ {
+			dst.Data[dst.index(i, j)] = f(m.Data[m.index(i, j)])
+		}
+	}
+}
+
+func sigmoid(v float64) float64 { return 1 / (1 + math.Exp(-v)) }
+
+func dSigmoid(v float64) float64 { return v * (1 - v) }
@@ -1,10 +1,32 @@
 package matrix
 
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"neuraln/errors"
+)
+
 /*Matrix it works only with float64 type*/
+//
+// The elements are stored in the single slice Data: element (i, j) is
+// Data[Offset+i*RowStride+j*ColStride]. Matrices created by New are dense and
+// row-major; views created by RowView, ColumnView, View and T share the Data of
+// the matrix they were created from, so writes through a view are visible in it.
+//
+// Matrix holds one slice per row that shares its storage with Data, so existing
+// code can keep reading and writing m.Matrix[i][j]. It is nil for views whose rows
+// are not contiguous, such as transposed views, which are read with At and written
+// with Set. Code that assigns new slices to Matrix, or to its rows, keeps working:
+// every operation first copies such rows back into Data.
 type Matrix struct {
 	Matrix [][]float64
 	Col    int
 	Row    int
+
+	Data      []float64
+	Offset    int
+	RowStride int
+	ColStride int
 }
 
 // NewMatrix creates a new Matrix with the specified number of rows and columns.
@@ -15,43 +37,312 @@ func NewMatrix(Row, Col int) *Matrix {
 // NewFromArray creates a new Matrix from a given slice of float64 values.
 func NewFromArray(array []float64) *Matrix {
 	nMatrix := NewMatrix(len(array), 1)
-	for i, v := range array {
-		nMatrix.Matrix[i][0] = v
+	copy(nMatrix.Data, array)
+	return nMatrix
+}
+
+// NewFromColumns creates a new Matrix whose columns are the given slices. All
+// slices must have the same length.
+func NewFromColumns(columns [][]float64) *Matrix {
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0])
+	}
+
+	nMatrix := NewMatrix(rows, len(columns))
+	for j, column := range columns {
+		for i, v := range column {
+			nMatrix.Data[i*nMatrix.Col+j] = v
+		}
 	}
 	return nMatrix
 }
 
+// NewFromData creates a dense row-major Matrix that uses data, which must hold
+// Row*Col values, as its storage without copying it.
+func NewFromData(Row, Col int, data []float64) *Matrix {
+	return newView(data, Row, Col, 0, Col, 1)
+}
+
 // New creates a new Matrix with the specified number of rows and columns.
 func New(Row, Col int) *Matrix {
-	m := Matrix{
-		Col:    Col,
-		Row:    Row,
-		Matrix: make([][]float64, Row),
+	return NewFromData(Row, Col, make([]float64, Row*Col))
+}
+
+// newView creates a Matrix over data with the given shape and layout.
+func newView(data []float64, Row, Col, offset, rowStride, colStride int) *Matrix {
+	m := &Matrix{
+		Col:       Col,
+		Row:       Row,
+		Data:      data,
+		Offset:    offset,
+		RowStride: rowStride,
+		ColStride: colStride,
+	}
+	m.Matrix = m.rows()
+	return m
+}
+
+// rows returns the row slices aliasing Data, or nil if the rows of the Matrix are
+// not contiguous.
+func (m *Matrix) rows() [][]float64 {
+	if m.ColStride != 1 && m.Col > 1 {
+		return nil
 	}
-	for i := range m.Matrix {
-		m.Matrix[i] = make([]float64, Col)
+
+	rows := make([][]float64, m.Row)
+	for i := range rows {
+		start := m.Offset + i*m.RowStride
+		rows[i] = m.Data[start : start+m.Col : start+m.Col]
 	}
-	return &m
+	return rows
 }
 
-// Map applies a function to each element of the Matrix and returns a new Matrix.
-func (m *Matrix) Map(f func(float64) float64) *Matrix {
+// sync copies rows that were assigned to m.Matrix, and therefore no longer share
+// the storage of Data, back into Data. Matrices built as literals without Data get
+// dense storage of their own.
+func (m *Matrix) sync() {
+	changed := false
+	if m.Data == nil {
+		m.RowStride, m.ColStride, m.Offset = m.Col, 1, 0
+		m.Data = make([]float64, m.Row*m.Col)
+		changed = true
+	}
+	if m.Matrix == nil || m.Col == 0 {
+		if changed {
+			m.Matrix = m.rows()
+		}
+		return
+	}
+
+	for i := 0; i < m.Row && i < len(m.Matrix); i++ {
+		row := m.Matrix[i]
+		start := m.Offset + i*m.RowStride
+		if len(row) > 0 && (m.ColStride == 1 || m.Col == 1) && &row[0] == &m.Data[start] {
+			continue
+		}
+		for j := 0; j < m.Col && j < len(row); j++ {
+			m.Data[start+j*m.ColStride] = row[j]
+		}
+		changed = true
+	}
+	if changed {
+		m.Matrix = m.rows()
+	}
+}
+
+// index returns the position of element (i, j) in Data.
+func (m *Matrix) index(i, j int) int {
+	return m.Offset + i*m.RowStride + j*m.ColStride
+}
+
+// At returns element (i, j). At does not see values assigned to new slices in
+// m.Matrix until an operation has copied them back into Data.
+func (m *Matrix) At(i, j int) float64 {
+	if m.Data == nil {
+		m.sync()
+	}
+	return m.Data[m.index(i, j)]
+}
+
+// Set sets element (i, j) to v.
+func (m *Matrix) Set(i, j int, v float64) {
+	if m.Data == nil {
+		m.sync()
+	}
+	m.Data[m.index(i, j)] = v
+}
+
+// IsDense reports whether the elements are stored row after row without gaps, so
+// that Data[Offset:Offset+Row*Col] holds the Matrix in row-major order.
+func (m *Matrix) IsDense() bool {
+	return (m.ColStride == 1 || m.Col <= 1) && (m.RowStride == m.Col || m.Row <= 1)
+}
+
+// Contiguous returns the Matrix itself if it is dense, otherwise a dense copy.
+func (m *Matrix) Contiguous() *Matrix {
+	m.sync()
+	if m.IsDense() {
+		return m
+	}
+	return m.Copy()
+}
+
+// dense returns the elements in row-major order, sharing the storage of Data when
+// the Matrix is dense.
+func (m *Matrix) dense() []float64 {
+	c := m.Contiguous()
+	return c.Data[c.Offset : c.Offset+c.Row*c.Col]
+}
+
+// RowView returns row i as a 1 x Col Matrix that shares the storage of m.
+func (m *Matrix) RowView(i int) *Matrix {
+	return m.View(i, 0, 1, m.Col)
+}
+
+// ColumnView returns column j as a Row x 1 Matrix that shares the storage of m.
+func (m *Matrix) ColumnView(j int) *Matrix {
+	return m.View(0, j, m.Row, 1)
+}
+
+// View returns the rows x cols sub-matrix starting at element (i, j) without
+// copying it. It panics if the sub-matrix does not fit into m.
+func (m *Matrix) View(i, j, rows, cols int) *Matrix {
+	if i < 0 || j < 0 || rows < 0 || cols < 0 || i+rows > m.Row || j+cols > m.Col {
+		panic("matrix: view out of range")
+	}
+	m.sync()
+	return newView(m.Data, rows, cols, m.index(i, j), m.RowStride, m.ColStride)
+}
+
+// T returns the transpose of the Matrix in O(1) as a view that shares the storage
+// of m. Transpose returns a dense copy instead.
+func (m *Matrix) T() *Matrix {
+	m.sync()
+	return newView(m.Data, m.Col, m.Row, m.Offset, m.ColStride, m.RowStride)
+}
+
+// Copy returns a dense deep copy of the Matrix.
+func (m *Matrix) Copy() *Matrix {
+	m.sync()
 	result := New(m.Row, m.Col)
 	for i := 0; i < m.Row; i++ {
 		for j := 0; j < m.Col; j++ {
-			result.Matrix[i][j] = f(m.Matrix[i][j])
+			result.Data[i*m.Col+j] = m.Data[m.index(i, j)]
 		}
 	}
 	return result
 }
 
+// Map applies a function to each element of the Matrix and returns a new Matrix.
+func (m *Matrix) Map(f func(float64) float64) *Matrix {
+	result := New(m.Row, m.Col)
+	mapInto(result, m, f)
+	return result
+}
+
 // Flatten converts the Matrix into a 1D slice.
 func (m *Matrix) Flatten() []float64 {
+	m.sync()
 	flat := make([]float64, m.Row*m.Col)
 	for i := 0; i < m.Row; i++ {
 		for j := 0; j < m.Col; j++ {
-			flat[i*m.Col+j] = m.Matrix[i][j]
+			flat[i*m.Col+j] = m.Data[m.index(i, j)]
 		}
 	}
 	return flat
 }
+
+// BroadcastAdd adds a column vector to every column of the Matrix and returns a new Matrix.
+func (m *Matrix) BroadcastAdd(column *Matrix) (*Matrix, error) {
+	if column.Col != 1 || column.Row != m.Row {
+		return nil, errors.ErrColumnVectorMismatch
+	}
+
+	m.sync()
+	column.sync()
+	result := New(m.Row, m.Col)
+	for i := 0; i < m.Row; i++ {
+		v := column.Data[column.index(i, 0)]
+		for j := 0; j < m.Col; j++ {
+			result.Data[i*m.Col+j] = m.Data[m.index(i, j)] + v
+		}
+	}
+	return result, nil
+}
+
+// SumColumns adds up the columns of the Matrix and returns them as a column vector.
+func (m *Matrix) SumColumns() *Matrix {
+	m.sync()
+	result := New(m.Row, 1)
+	for i := 0; i < m.Row; i++ {
+		for j := 0; j < m.Col; j++ {
+			result.Data[i] += m.Data[m.index(i, j)]
+		}
+	}
+	return result
+}
+
+// Column returns a copy of the j-th column of the Matrix.
+func (m *Matrix) Column(j int) []float64 {
+	m.sync()
+	column := make([]float64, m.Row)
+	for i := 0; i < m.Row; i++ {
+		column[i] = m.Data[m.index(i, j)]
+	}
+	return column
+}
+
+// jsonMatrix is the persisted form of a Matrix, which nests the rows.
+type jsonMatrix struct {
+	Matrix [][]float64
+	Col    int
+	Row    int
+}
+
+// MarshalJSON stores the Matrix as nested rows, whatever its layout.
+func (m *Matrix) MarshalJSON() ([]byte, error) {
+	m.sync()
+	rows := make([][]float64, m.Row)
+	for i := range rows {
+		rows[i] = m.RowView(i).Flatten()
+	}
+	return json.Marshal(jsonMatrix{Matrix: rows, Col: m.Col, Row: m.Row})
+}
+
+// UnmarshalJSON restores a Matrix written by MarshalJSON as a dense Matrix.
+func (m *Matrix) UnmarshalJSON(data []byte) error {
+	var aux jsonMatrix
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*m = *New(aux.Row, aux.Col)
+	for i := 0; i < aux.Row && i < len(aux.Matrix); i++ {
+		copy(m.Matrix[i], aux.Matrix[i])
+	}
+	return nil
+}
+
+// RandomizeWith fills a new Matrix with random values between -1 and 1 drawn from r,
+// so the same seed always produces the same Matrix.
+func (m *Matrix) RandomizeWith(r *rand.Rand) *Matrix {
+	return New(m.Row, m.Col).Fill(r, Uniform{Low: -1, High: 1})
+}
+
+// Distribution draws random values from r.
+type Distribution interface {
+	Sample(r *rand.Rand) float64
+}
+
+// Uniform draws values uniformly from [Low, High).
+type Uniform struct {
+	Low  float64
+	High float64
+}
+
+func (u Uniform) Sample(r *rand.Rand) float64 {
+	return u.Low + r.Float64()*(u.High-u.Low)
+}
+
+// Normal draws values from a normal distribution.
+type Normal struct {
+	Mean   float64
+	StdDev float64
+}
+
+func (n Normal) Sample(r *rand.Rand) float64 {
+	return n.Mean + r.NormFloat64()*n.StdDev
+}
+
+// Fill sets the elements of m, row by row, to values drawn from d using r and
+// returns m. m is written through its own layout, so it may be a view.
+func (m *Matrix) Fill(r *rand.Rand, d Distribution) *Matrix {
+	m.sync()
+	for i := 0; i < m.Row; i++ {
+		for j := 0; j < m.Col; j++ {
+			m.Data[m.index(i, j)] = d.Sample(r)
+		}
+	}
+	return m
+}
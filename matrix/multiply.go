@@ -0,0 +1,174 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// blockRows, blockDepth and blockCols are the tile sizes of gemm. A tile of the
+	// right operand, blockDepth x blockCols, stays in the cache while every row of
+	// a band is multiplied with it.
+	blockRows  = 64
+	blockDepth = 128
+	blockCols  = 512
+	// parallelThreshold is the number of multiply-adds below which gemm stays on
+	// the calling goroutine, where starting goroutines would cost more than it
+	// saves.
+	parallelThreshold = 1 << 18
+)
+
+// gemm computes c = alpha*a*b + beta*c, where a has as many columns as b has rows
+// and c has the shape of their product. The rows of c are split into bands that
+// are spread over up to GOMAXPROCS goroutines.
+//
+// When b has contiguous rows, every band is computed tile by tile, walking the
+// rows of b instead of its columns; each element then sums its products in
+// increasing order of k, so with alpha 1 and beta 0 the result matches the naive
+// triple loop exactly. When b has contiguous columns instead, as the transpose of
+// a dense matrix does, and a has contiguous rows, every element is the dot product
+// of two contiguous slices. Other layouts of b are copied first.
+func gemm(alpha float64, a, b *Matrix, beta float64, c *Matrix) {
+	a.sync()
+	b.sync()
+	c.sync()
+
+	out := c
+	if c.ColStride != 1 && c.Col > 1 {
+		// The kernels write rows of c as slices
+		out = c.Copy()
+	}
+	scaleRows(out, beta)
+
+	if alpha != 0 && a.Col > 0 {
+		kernel := axpyRows
+		switch {
+		case b.ColStride == 1 || b.Col <= 1:
+		case (b.RowStride == 1 || b.Row <= 1) && (a.ColStride == 1 || a.Col <= 1):
+			kernel = dotRows
+		default:
+			b = b.Copy()
+		}
+		parallelRows(a.Row, a.Row*a.Col*b.Col, func(start, end int) {
+			kernel(alpha, a, b, out, start, end)
+		})
+	}
+
+	if out != c {
+		for i := 0; i < c.Row; i++ {
+			for j := 0; j < c.Col; j++ {
+				c.Data[c.index(i, j)] = out.Data[out.index(i, j)]
+			}
+		}
+	}
+}
+
+// parallelRows calls f for bands of blockRows rows out of rows, spread over up to
+// GOMAXPROCS goroutines when work, the number of multiply-adds, is large enough.
+func parallelRows(rows, work int, f func(start, end int)) {
+	bands := (rows + blockRows - 1) / blockRows
+	workers := runtime.GOMAXPROCS(0)
+	if workers > bands {
+		workers = bands
+	}
+	if workers <= 1 || work < parallelThreshold {
+		f(0, rows)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for band := w; band < bands; band += workers {
+				end := (band + 1) * blockRows
+				if end > rows {
+					end = rows
+				}
+				f(band*blockRows, end)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// scaleRows multiplies every element of m, which has contiguous rows, by beta. A
+// beta of 0 clears m, whatever it held before.
+func scaleRows(m *Matrix, beta float64) {
+	if beta == 1 {
+		return
+	}
+	for i := 0; i < m.Row; i++ {
+		row := m.Data[m.index(i, 0) : m.index(i, 0)+m.Col]
+		for j := range row {
+			if beta == 0 {
+				row[j] = 0
+			} else {
+				row[j] *= beta
+			}
+		}
+	}
+}
+
+// axpyRows adds alpha times rows [start, end) of the product of a and b to c. b
+// and c must have contiguous rows.
+func axpyRows(alpha float64, a, b, c *Matrix, start, end int) {
+	for kk := 0; kk < a.Col; kk += blockDepth {
+		kEnd := kk + blockDepth
+		if kEnd > a.Col {
+			kEnd = a.Col
+		}
+		for jj := 0; jj < b.Col; jj += blockCols {
+			jEnd := jj + blockCols
+			if jEnd > b.Col {
+				jEnd = b.Col
+			}
+			for i := start; i < end; i++ {
+				out := c.Data[c.index(i, jj) : c.index(i, jj)+jEnd-jj]
+				k := kk
+				// Four rows of b at a time, so every element of out is loaded and
+				// stored once per four products
+				for ; k+4 <= kEnd; k += 4 {
+					a0, a1, a2, a3 := alpha*a.Data[a.index(i, k)], alpha*a.Data[a.index(i, k+1)], alpha*a.Data[a.index(i, k+2)], alpha*a.Data[a.index(i, k+3)]
+					b0 := b.Data[b.index(k, jj) : b.index(k, jj)+len(out)]
+					b1 := b.Data[b.index(k+1, jj) : b.index(k+1, jj)+len(out)]
+					b2 := b.Data[b.index(k+2, jj) : b.index(k+2, jj)+len(out)]
+					b3 := b.Data[b.index(k+3, jj) : b.index(k+3, jj)+len(out)]
+					for j := range out {
+						sum := out[j]
+						sum += a0 * b0[j]
+						sum += a1 * b1[j]
+						sum += a2 * b2[j]
+						sum += a3 * b3[j]
+						out[j] = sum
+					}
+				}
+				for ; k < kEnd; k++ {
+					aik := alpha * a.Data[a.index(i, k)]
+					row := b.Data[b.index(k, jj) : b.index(k, jj)+len(out)]
+					for j, v := range row {
+						out[j] += aik * v
+					}
+				}
+			}
+		}
+	}
+}
+
+// dotRows adds alpha times rows [start, end) of the product of a and b to c. a
+// must have contiguous rows, b contiguous columns and c contiguous rows.
+func dotRows(alpha float64, a, b, c *Matrix, start, end int) {
+	for i := start; i < end; i++ {
+		row := a.Data[a.index(i, 0) : a.index(i, 0)+a.Col]
+		out := c.Data[c.index(i, 0) : c.index(i, 0)+c.Col]
+		for j := range out {
+			column := b.Data[b.index(0, j) : b.index(0, j)+len(row)]
+			sum := 0.0
+			for k, v := range row {
+				sum += v * column[k]
+			}
+			out[j] += alpha * sum
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package matrix_test
+
+import (
+	goerrors "errors"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"testing"
+)
+
+// countingBackend delegates to the cpu backend and counts the multiplications.
+type countingBackend struct {
+	matrix.Backend
+	muls int
+}
+
+func (*countingBackend) Name() string { return "counting" }
+
+func (c *countingBackend) Mul(a, b *matrix.Matrix) (*matrix.Matrix, error) {
+	c.muls++
+	return c.Backend.Mul(a, b)
+}
+
+func TestBackendRegistry(t *testing.T) {
+	found := false
+	for _, name := range matrix.Backends() {
+		found = found || name == "cpu"
+	}
+	if !found {
+		t.Fatalf("Expected the cpu backend to be registered, got %v", matrix.Backends())
+	}
+	if matrix.DefaultBackend().Name() != "cpu" {
+		t.Errorf("Expected cpu to be the default backend, got %s", matrix.DefaultBackend().Name())
+	}
+
+	if _, err := matrix.GetBackend("missing"); !goerrors.Is(err, errors.ErrUnknownBackend) {
+		t.Errorf("Expected ErrUnknownBackend, got %v", err)
+	}
+	if err := matrix.SetDefaultBackend("missing"); !goerrors.Is(err, errors.ErrUnknownBackend) {
+		t.Errorf("Expected ErrUnknownBackend, got %v", err)
+	}
+}
+
+func TestSetDefaultBackend(t *testing.T) {
+	cpu, err := matrix.GetBackend("cpu")
+	if err != nil {
+		t.Fatalf("GetBackend failed: %v", err)
+	}
+	counting := &countingBackend{Backend: cpu}
+	matrix.RegisterBackend(counting)
+	if err := matrix.SetDefaultBackend("counting"); err != nil {
+		t.Fatalf("SetDefaultBackend failed: %v", err)
+	}
+	defer matrix.SetDefaultBackend("cpu")
+
+	result, err := newSequence(2, 3).DotProduct(newSequence(3, 2))
+	if err != nil {
+		t.Fatalf("DotProduct failed: %v", err)
+	}
+	assertMatrix(t, result, [][]float64{{22, 28}, {49, 64}})
+	if counting.muls != 1 {
+		t.Errorf("Expected DotProduct to run on the default backend, got %d multiplications", counting.muls)
+	}
+}
+
+func TestCPUBackendViews(t *testing.T) {
+	cpu, err := matrix.GetBackend("cpu")
+	if err != nil {
+		t.Fatalf("GetBackend failed: %v", err)
+	}
+
+	m := newSequence(2, 3)
+	sum, err := cpu.Add(m.T(), m.Transpose())
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	assertMatrix(t, sum, [][]float64{{2, 8}, {4, 10}, {6, 12}})
+
+	product, err := cpu.Mul(m, m.T())
+	if err != nil {
+		t.Fatalf("Mul failed: %v", err)
+	}
+	assertMatrix(t, product, [][]float64{{14, 32}, {32, 77}})
+
+	if _, err := cpu.Hadamard(m, m.T()); !goerrors.Is(err, errors.ErrRowsColsMustEqual) {
+		t.Errorf("Expected ErrRowsColsMustEqual, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package matrix_test
+
+import (
+	"neuraln/errors"
+	"neuraln/matrix"
+	"testing"
+)
+
+func TestNewFromColumns(t *testing.T) {
+	m := matrix.NewFromColumns([][]float64{{1, 2, 3}, {4, 5, 6}})
+
+	expected := [][]float64{
+		{1, 4},
+		{2, 5},
+		{3, 6},
+	}
+
+	if m.Row != 3 || m.Col != 2 {
+		t.Fatalf("Expected a 3x2 matrix, got %dx%d", m.Row, m.Col)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			if m.Matrix[i][j] != expected[i][j] {
+				t.Errorf("Expected %v, got %v", expected, m.Matrix)
+				return
+			}
+		}
+	}
+
+	column := m.Column(1)
+	if len(column) != 3 || column[0] != 4 || column[1] != 5 || column[2] != 6 {
+		t.Errorf("Expected column [4 5 6], got %v", column)
+	}
+}
+
+func TestBroadcastAdd(t *testing.T) {
+	a := matrix.New(2, 3)
+	a.Matrix = [][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+
+	result, err := a.BroadcastAdd(matrix.NewFromArray([]float64{10, 20}))
+	if err != nil {
+		t.Fatalf("BroadcastAdd failed: %v", err)
+	}
+
+	expected := [][]float64{
+		{11, 12, 13},
+		{24, 25, 26},
+	}
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			if result.Matrix[i][j] != expected[i][j] {
+				t.Errorf("Expected %v, got %v", expected, result.Matrix)
+				return
+			}
+		}
+	}
+
+	if _, err := a.BroadcastAdd(matrix.NewFromArray([]float64{1, 2, 3})); err != errors.ErrColumnVectorMismatch {
+		t.Errorf("Expected %v, got %v", errors.ErrColumnVectorMismatch, err)
+	}
+}
+
+func TestSumColumns(t *testing.T) {
+	a := matrix.New(2, 3)
+	a.Matrix = [][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+
+	result := a.SumColumns()
+	if result.Row != 2 || result.Col != 1 || result.Matrix[0][0] != 6 || result.Matrix[1][0] != 15 {
+		t.Errorf("Expected [[6] [15]], got %v", result.Matrix)
+	}
+}
@@ -0,0 +1,57 @@
+package matrix_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"neuraln/matrix"
+	"testing"
+)
+
+func TestFillUniform(t *testing.T) {
+	m := matrix.New(100, 100).Fill(rand.New(rand.NewPCG(1, 1)), matrix.Uniform{Low: 2, High: 5})
+
+	for _, v := range m.Flatten() {
+		if v < 2 || v >= 5 {
+			t.Fatalf("Expected values in [2, 5), got %v", v)
+		}
+	}
+}
+
+func TestFillNormal(t *testing.T) {
+	m := matrix.New(100, 100).Fill(rand.New(rand.NewPCG(1, 1)), matrix.Normal{Mean: 3, StdDev: 0.5})
+
+	values := m.Flatten()
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+
+	if math.Abs(mean-3) > 0.05 || math.Abs(stddev-0.5) > 0.05 {
+		t.Errorf("Expected mean 3 and standard deviation 0.5, got %v and %v", mean, stddev)
+	}
+}
+
+func TestFillInPlace(t *testing.T) {
+	m := matrix.New(3, 4)
+	view := m.View(1, 1, 2, 2).T()
+	if filled := view.Fill(rand.New(rand.NewPCG(1, 1)), matrix.Uniform{Low: 1, High: 2}); filled != view {
+		t.Fatalf("Expected Fill to return its receiver")
+	}
+
+	// Only the elements behind the view are filled
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			inside := i >= 1 && j >= 1 && j <= 2
+			if v := m.At(i, j); inside != (v >= 1) {
+				t.Errorf("Element (%d, %d): got %v", i, j, v)
+			}
+		}
+	}
+}
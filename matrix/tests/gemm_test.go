@@ -0,0 +1,114 @@
+package matrix_test
+
+import (
+	goerrors "errors"
+	"math"
+	"math/rand/v2"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"testing"
+)
+
+// naiveGemm is the reference for Gemm: alpha*op(a)*op(b) + beta*c as a new matrix.
+func naiveGemm(transA, transB bool, alpha float64, a, b *matrix.Matrix, beta float64, c *matrix.Matrix) *matrix.Matrix {
+	if transA {
+		a = a.Transpose()
+	}
+	if transB {
+		b = b.Transpose()
+	}
+	product := naiveDotProduct(a, b)
+	result := matrix.New(c.Row, c.Col)
+	for i := 0; i < c.Row; i++ {
+		for j := 0; j < c.Col; j++ {
+			result.Set(i, j, alpha*product.At(i, j)+beta*c.At(i, j))
+		}
+	}
+	return result
+}
+
+func assertGemm(t *testing.T, transA, transB bool, alpha float64, a, b *matrix.Matrix, beta float64, c *matrix.Matrix) {
+	t.Helper()
+	expected := naiveGemm(transA, transB, alpha, a, b, beta, c)
+	if err := matrix.Gemm(transA, transB, alpha, a, b, beta, c); err != nil {
+		t.Fatalf("Gemm failed: %v", err)
+	}
+	for i := 0; i < expected.Row; i++ {
+		for j := 0; j < expected.Col; j++ {
+			if math.Abs(c.At(i, j)-expected.At(i, j)) > 1e-9 {
+				t.Fatalf("transA=%v transB=%v at (%d, %d): expected %v, got %v", transA, transB, i, j, expected.At(i, j), c.At(i, j))
+			}
+		}
+	}
+}
+
+func TestGemmTransposes(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	// Small products and products large enough to run in parallel
+	for _, shape := range [][3]int{{3, 4, 5}, {130, 140, 150}} {
+		m, k, n := shape[0], shape[1], shape[2]
+		for _, transA := range []bool{false, true} {
+			for _, transB := range []bool{false, true} {
+				a, b := newRandom(r, m, k), newRandom(r, k, n)
+				if transA {
+					a = newRandom(r, k, m)
+				}
+				if transB {
+					b = newRandom(r, n, k)
+				}
+				assertGemm(t, transA, transB, 0.5, a, b, 2, newRandom(r, m, n))
+			}
+		}
+	}
+}
+
+func TestGemmBetaZeroIgnoresC(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	c := matrix.New(3, 2)
+	for i := range c.Data {
+		c.Data[i] = math.NaN()
+	}
+	a, b := newRandom(r, 3, 4), newRandom(r, 4, 2)
+	if err := matrix.Gemm(false, false, 1, a, b, 0, c); err != nil {
+		t.Fatalf("Gemm failed: %v", err)
+	}
+	expected, _ := a.DotProduct(b)
+	for i := range c.Data {
+		if c.Data[i] != expected.Data[i] {
+			t.Fatalf("Expected %v, got %v", expected.Data, c.Data)
+		}
+	}
+}
+
+func TestGemmIntoView(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	parent := newRandom(r, 6, 8)
+	before := parent.Copy()
+
+	// A transposed view of a block of parent, so c has neither contiguous rows
+	// nor a layout of its own
+	c := parent.View(1, 2, 4, 3).T()
+	assertGemm(t, true, false, -1, newRandom(r, 5, 3), newRandom(r, 5, 4), 0.5, c)
+
+	for i := 0; i < parent.Row; i++ {
+		for j := 0; j < parent.Col; j++ {
+			inside := i >= 1 && i < 5 && j >= 2 && j < 5
+			if !inside && parent.At(i, j) != before.At(i, j) {
+				t.Fatalf("Expected Gemm to leave (%d, %d) outside the view unchanged", i, j)
+			}
+		}
+	}
+}
+
+func TestGemmDimensions(t *testing.T) {
+	a, b := matrix.New(2, 3), matrix.New(3, 4)
+	if err := matrix.Gemm(false, false, 1, a, b, 0, matrix.New(2, 4)); err != nil {
+		t.Errorf("Expected matching shapes to succeed, got %v", err)
+	}
+	if err := matrix.Gemm(true, false, 1, a, b, 0, matrix.New(3, 4)); !goerrors.Is(err, errors.ErrRowsMustEqualColumns) {
+		t.Errorf("Expected ErrRowsMustEqualColumns, got %v", err)
+	}
+	if err := matrix.Gemm(false, false, 1, a, b, 0, matrix.New(4, 2)); !goerrors.Is(err, errors.ErrMatricesDimensionsMustMatch) {
+		t.Errorf("Expected ErrMatricesDimensionsMustMatch, got %v", err)
+	}
+}
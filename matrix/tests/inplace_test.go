@@ -0,0 +1,113 @@
+package matrix_test
+
+import (
+	goerrors "errors"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"testing"
+)
+
+func TestIntoMatchesAllocating(t *testing.T) {
+	a, b := newSequence(2, 3), newSequence(2, 3).ScalerMul(0.5)
+
+	sum, _ := a.AddFromMatrix(b)
+	dst := matrix.New(2, 3)
+	if err := a.AddInto(dst, b); err != nil {
+		t.Fatalf("AddInto failed: %v", err)
+	}
+	assertMatrix(t, dst, [][]float64{sum.Matrix[0], sum.Matrix[1]})
+
+	difference, _ := a.SubtractMatrix(b)
+	if err := a.SubtractInto(dst, b); err != nil {
+		t.Fatalf("SubtractInto failed: %v", err)
+	}
+	assertMatrix(t, dst, [][]float64{difference.Matrix[0], difference.Matrix[1]})
+
+	product, _ := a.HadProduct(b)
+	if err := a.HadProductInto(dst, b); err != nil {
+		t.Fatalf("HadProductInto failed: %v", err)
+	}
+	assertMatrix(t, dst, [][]float64{product.Matrix[0], product.Matrix[1]})
+
+	sigmoid := a.Sigmoid()
+	if err := a.SigmoidInto(dst); err != nil {
+		t.Fatalf("SigmoidInto failed: %v", err)
+	}
+	assertMatrix(t, dst, [][]float64{sigmoid.Matrix[0], sigmoid.Matrix[1]})
+
+	transposed := matrix.New(3, 2)
+	if err := a.TransposeInto(transposed); err != nil {
+		t.Fatalf("TransposeInto failed: %v", err)
+	}
+	assertMatrix(t, transposed, [][]float64{{1, 4}, {2, 5}, {3, 6}})
+
+	dot := matrix.New(2, 2)
+	if err := a.DotProductInto(dot, transposed); err != nil {
+		t.Fatalf("DotProductInto failed: %v", err)
+	}
+	assertMatrix(t, dot, [][]float64{{14, 32}, {32, 77}})
+
+	sums := matrix.New(2, 1)
+	if err := a.SumColumnsInto(sums); err != nil {
+		t.Fatalf("SumColumnsInto failed: %v", err)
+	}
+	assertMatrix(t, sums, [][]float64{{6}, {15}})
+}
+
+func TestInPlace(t *testing.T) {
+	m := newSequence(2, 2)
+	if err := m.AddInPlace(newSequence(2, 2)); err != nil {
+		t.Fatalf("AddInPlace failed: %v", err)
+	}
+	m.ScalerMulInPlace(0.5)
+	if err := m.BroadcastAddInPlace(matrix.NewFromArray([]float64{1, -1})); err != nil {
+		t.Fatalf("BroadcastAddInPlace failed: %v", err)
+	}
+	if err := m.HadProductInPlace(m); err != nil {
+		t.Fatalf("HadProductInPlace failed: %v", err)
+	}
+	assertMatrix(t, m, [][]float64{{4, 9}, {4, 9}})
+
+	// The compatibility rows share the storage and see the changes
+	if m.Matrix[1][1] != 9 {
+		t.Errorf("Expected m.Matrix to reflect the in-place operations, got %v", m.Matrix)
+	}
+}
+
+func TestIntoView(t *testing.T) {
+	parent := matrix.New(3, 4)
+	column := parent.ColumnView(2)
+	if err := newSequence(3, 1).CopyInto(column); err != nil {
+		t.Fatalf("CopyInto failed: %v", err)
+	}
+	assertMatrix(t, parent, [][]float64{{0, 0, 1, 0}, {0, 0, 2, 0}, {0, 0, 3, 0}})
+}
+
+func TestIntoDimensions(t *testing.T) {
+	a := newSequence(2, 3)
+	if err := a.AddInto(matrix.New(3, 2), a); !goerrors.Is(err, errors.ErrDestinationDimensions) {
+		t.Errorf("Expected ErrDestinationDimensions, got %v", err)
+	}
+	if err := a.AddInto(matrix.New(2, 3), matrix.New(3, 2)); !goerrors.Is(err, errors.ErrMatricesDimensionsMustMatch) {
+		t.Errorf("Expected ErrMatricesDimensionsMustMatch, got %v", err)
+	}
+	if err := a.DotProductInto(matrix.New(2, 2), a); !goerrors.Is(err, errors.ErrRowsMustEqualColumns) {
+		t.Errorf("Expected ErrRowsMustEqualColumns, got %v", err)
+	}
+	if err := a.SigmoidInto(matrix.New(2, 2)); !goerrors.Is(err, errors.ErrDestinationDimensions) {
+		t.Errorf("Expected ErrDestinationDimensions, got %v", err)
+	}
+}
+
+func TestIntoDoesNotAllocate(t *testing.T) {
+	a, b, dst := newSequence(8, 8), newSequence(8, 8), matrix.New(8, 8)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = a.AddInto(dst, b)
+		_ = a.HadProductInto(dst, b)
+		_ = a.SigmoidInto(dst)
+		dst.ScalerMulInPlace(2)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected the Into and InPlace operations not to allocate, got %v allocations", allocs)
+	}
+}
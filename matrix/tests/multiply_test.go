@@ -0,0 +1,77 @@
+package matrix_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"neuraln/matrix"
+	"runtime"
+	"testing"
+)
+
+func newRandom(r *rand.Rand, rows, cols int) *matrix.Matrix {
+	m := matrix.New(rows, cols)
+	for i := range m.Data {
+		m.Data[i] = r.Float64()*2 - 1
+	}
+	return m
+}
+
+// naiveDotProduct is the reference triple loop DotProduct must agree with.
+func naiveDotProduct(a, b *matrix.Matrix) *matrix.Matrix {
+	result := matrix.New(a.Row, b.Col)
+	for i := 0; i < a.Row; i++ {
+		for j := 0; j < b.Col; j++ {
+			sum := 0.0
+			for k := 0; k < a.Col; k++ {
+				sum += a.At(i, k) * b.At(k, j)
+			}
+			result.Set(i, j, sum)
+		}
+	}
+	return result
+}
+
+func assertProduct(t *testing.T, a, b *matrix.Matrix) {
+	t.Helper()
+	actual, err := a.DotProduct(b)
+	if err != nil {
+		t.Fatalf("DotProduct failed: %v", err)
+	}
+	expected := naiveDotProduct(a, b)
+	if actual.Row != expected.Row || actual.Col != expected.Col {
+		t.Fatalf("Expected a %dx%d matrix, got %dx%d", expected.Row, expected.Col, actual.Row, actual.Col)
+	}
+	for i := 0; i < expected.Row; i++ {
+		for j := 0; j < expected.Col; j++ {
+			if math.Abs(actual.At(i, j)-expected.At(i, j)) > 1e-9 {
+				t.Fatalf("At (%d, %d): expected %v, got %v", i, j, expected.At(i, j), actual.At(i, j))
+			}
+		}
+	}
+}
+
+func TestDotProductMatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	// Small products stay on one goroutine, large ones have partial tiles on
+	// every side
+	shapes := [][3]int{{1, 1, 1}, {3, 5, 2}, {7, 130, 9}, {150, 131, 517}, {200, 300, 70}}
+	for _, shape := range shapes {
+		assertProduct(t, newRandom(r, shape[0], shape[1]), newRandom(r, shape[1], shape[2]))
+	}
+}
+
+func TestDotProductParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	r := rand.New(rand.NewPCG(3, 4))
+	assertProduct(t, newRandom(r, 257, 140), newRandom(r, 140, 600))
+}
+
+func TestDotProductViews(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	a, b := newRandom(r, 140, 90), newRandom(r, 300, 140)
+
+	// Transposed and sliced operands are multiplied without copying them first
+	assertProduct(t, a.T(), b.T())
+	assertProduct(t, b.View(10, 5, 100, 130), a.View(0, 3, 130, 80))
+}
@@ -1,6 +1,7 @@
 package matrix_test
 
 import (
+	"math/rand/v2"
 	"neuraln/matrix"
 	"testing"
 )
@@ -21,3 +22,19 @@ func TestRandomize(t *testing.T) {
 		}
 	}
 }
+
+func TestRandomizeWith(t *testing.T) {
+	a := matrix.New(10, 10).RandomizeWith(rand.New(rand.NewPCG(1, 2)))
+	b := matrix.New(10, 10).RandomizeWith(rand.New(rand.NewPCG(1, 2)))
+
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			if a.Matrix[i][j] != b.Matrix[i][j] {
+				t.Fatalf("Expected identical sources to produce identical matrices")
+			}
+			if a.Matrix[i][j] < -1 || a.Matrix[i][j] >= 1 {
+				t.Errorf("Expected values in [-1, 1), got %v", a.Matrix[i][j])
+			}
+		}
+	}
+}
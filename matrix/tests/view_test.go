@@ -0,0 +1,131 @@
+package matrix_test
+
+import (
+	"encoding/json"
+	"neuraln/matrix"
+	"testing"
+)
+
+func newSequence(rows, cols int) *matrix.Matrix {
+	m := matrix.New(rows, cols)
+	for i := range m.Data {
+		m.Data[i] = float64(i + 1)
+	}
+	return m
+}
+
+func assertMatrix(t *testing.T, m *matrix.Matrix, expected [][]float64) {
+	t.Helper()
+	if m.Row != len(expected) || m.Col != len(expected[0]) {
+		t.Fatalf("Expected a %dx%d matrix, got %dx%d", len(expected), len(expected[0]), m.Row, m.Col)
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if m.At(i, j) != expected[i][j] {
+				t.Fatalf("At (%d, %d): expected %v, got %v", i, j, expected[i][j], m.At(i, j))
+			}
+		}
+	}
+}
+
+func TestFlatStorage(t *testing.T) {
+	m := newSequence(2, 3)
+	assertMatrix(t, m, [][]float64{{1, 2, 3}, {4, 5, 6}})
+	if !m.IsDense() || len(m.Data) != 6 {
+		t.Errorf("Expected New to create dense storage")
+	}
+
+	// The compatibility rows share the flat storage in both directions
+	m.Matrix[1][2] = 60
+	if m.Data[5] != 60 {
+		t.Errorf("Expected a write to m.Matrix to reach Data, got %v", m.Data[5])
+	}
+	m.Set(0, 1, 20)
+	if m.Matrix[0][1] != 20 {
+		t.Errorf("Expected Set to be visible in m.Matrix, got %v", m.Matrix[0][1])
+	}
+}
+
+func TestViews(t *testing.T) {
+	m := newSequence(3, 4)
+
+	assertMatrix(t, m.RowView(1), [][]float64{{5, 6, 7, 8}})
+	assertMatrix(t, m.ColumnView(2), [][]float64{{3}, {7}, {11}})
+	sub := m.View(1, 1, 2, 2)
+	assertMatrix(t, sub, [][]float64{{6, 7}, {10, 11}})
+
+	// Views share the storage of the matrix they were created from
+	sub.Set(1, 0, -10)
+	sub.Matrix[0][1] = -7
+	m.ColumnView(3).Matrix[2][0] = -12
+	assertMatrix(t, m, [][]float64{{1, 2, 3, 4}, {5, 6, -7, 8}, {9, -10, 11, -12}})
+
+	if sub.IsDense() {
+		t.Errorf("Expected a sub-matrix view not to be dense")
+	}
+	if c := sub.Contiguous(); !c.IsDense() || c.At(1, 0) != -10 {
+		t.Errorf("Expected Contiguous to return a dense copy")
+	}
+}
+
+func TestTransposeView(t *testing.T) {
+	m := newSequence(2, 3)
+	v := m.T()
+	assertMatrix(t, v, [][]float64{{1, 4}, {2, 5}, {3, 6}})
+	if v.Matrix != nil {
+		t.Errorf("Expected no compatibility rows for a transposed view")
+	}
+
+	v.Set(2, 0, 30)
+	if m.At(0, 2) != 30 {
+		t.Errorf("Expected the transposed view to share storage, got %v", m.At(0, 2))
+	}
+	assertMatrix(t, v.T(), [][]float64{{1, 2, 30}, {4, 5, 6}})
+
+	// Operations accept views and produce dense results
+	viewProduct, err := m.DotProduct(v)
+	if err != nil {
+		t.Fatalf("DotProduct failed: %v", err)
+	}
+	copyProduct, _ := m.DotProduct(m.Transpose())
+	assertMatrix(t, viewProduct, [][]float64{{905, 194}, {194, 77}})
+	assertMatrix(t, copyProduct, [][]float64{{905, 194}, {194, 77}})
+	if !viewProduct.IsDense() {
+		t.Errorf("Expected a dense result")
+	}
+	sum, _ := v.AddFromMatrix(m.Transpose())
+	assertMatrix(t, sum, [][]float64{{2, 8}, {4, 10}, {60, 12}})
+}
+
+func TestAssignedRows(t *testing.T) {
+	m := matrix.New(2, 2)
+	m.Matrix = [][]float64{{1, 2}, {3, 4}}
+	assertMatrix(t, m.Copy(), [][]float64{{1, 2}, {3, 4}})
+
+	m.Matrix[1] = []float64{5, 6}
+	if flat := m.Flatten(); flat[2] != 5 || flat[3] != 6 {
+		t.Errorf("Expected an assigned row to be picked up, got %v", flat)
+	}
+
+	literal := &matrix.Matrix{Matrix: [][]float64{{1, 2, 3}}, Row: 1, Col: 3}
+	assertMatrix(t, literal.ScalerMul(2), [][]float64{{2, 4, 6}})
+}
+
+func TestMatrixJSON(t *testing.T) {
+	data, err := json.Marshal(newSequence(2, 3).T())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"Matrix":[[1,4],[2,5],[3,6]],"Col":2,"Row":3}` {
+		t.Errorf("Expected the nested format, got %s", data)
+	}
+
+	var m matrix.Matrix
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	assertMatrix(t, &m, [][]float64{{1, 4}, {2, 5}, {3, 6}})
+	if !m.IsDense() {
+		t.Errorf("Expected an unmarshalled matrix to be dense")
+	}
+}
@@ -0,0 +1,266 @@
+package neural
+
+import (
+	"math"
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Activation is the non-linearity a layer applies to its weighted sums.
+type Activation interface {
+	// Name identifies the activation in exported models.
+	Name() string
+	// Forward applies the activation to the weighted sums z of a layer.
+	Forward(z *matrix.Matrix) *matrix.Matrix
+	// Backward returns the gradient with respect to the weighted sums z given the
+	// activations a = Forward(z) and the gradient with respect to a.
+	Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error)
+}
+
+var activations = newRegistry[Activation](errors.ErrUnknownActivation)
+
+func init() {
+	for _, activation := range []Activation{
+		Sigmoid{}, Tanh{}, ReLU{}, LeakyReLU{}, ELU{}, GELU{}, Linear{}, Softmax{},
+	} {
+		activations.register(activation.Name(), activation)
+	}
+}
+
+// RegisterActivation makes a custom activation available to ImportJSON. Models
+// using an activation that was not registered cannot be imported.
+func RegisterActivation(activation Activation) {
+	activations.register(activation.Name(), activation)
+}
+
+// accelerated is implemented by activations that are operations of a
+// matrix.Backend. A network runs them on its own backend, while Forward and
+// Backward use the default one.
+type accelerated interface {
+	forwardOn(backend matrix.Backend, z *matrix.Matrix) *matrix.Matrix
+	backwardOn(backend matrix.Backend, z, a, grad *matrix.Matrix) (*matrix.Matrix, error)
+}
+
+// Sigmoid squashes values into (0, 1). It is the default activation of every layer.
+type Sigmoid struct{}
+
+func (Sigmoid) Name() string { return "sigmoid" }
+
+func (s Sigmoid) Forward(z *matrix.Matrix) *matrix.Matrix {
+	return s.forwardOn(matrix.DefaultBackend(), z)
+}
+
+func (s Sigmoid) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	return s.backwardOn(matrix.DefaultBackend(), z, a, grad)
+}
+
+func (Sigmoid) forwardOn(backend matrix.Backend, z *matrix.Matrix) *matrix.Matrix {
+	return backend.Sigmoid(z)
+}
+
+func (Sigmoid) backwardOn(backend matrix.Backend, z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	derivative := backend.DSigmoid(a)
+	return hadamard(backend, derivative, derivative, grad)
+}
+
+// Tanh squashes values into (-1, 1).
+type Tanh struct{}
+
+func (Tanh) Name() string { return "tanh" }
+
+func (Tanh) Forward(z *matrix.Matrix) *matrix.Matrix {
+	return z.Map(math.Tanh)
+}
+
+func (Tanh) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	return chain(a.Map(func(x float64) float64 { return 1 - x*x }), grad)
+}
+
+// ReLU passes positive values through and zeroes out negative ones.
+type ReLU struct{}
+
+func (ReLU) Name() string { return "relu" }
+
+func (ReLU) Forward(z *matrix.Matrix) *matrix.Matrix {
+	return z.Map(func(x float64) float64 { return math.Max(0, x) })
+}
+
+func (ReLU) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	return chain(z.Map(func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return 0
+	}), grad)
+}
+
+// LeakyReLU behaves like ReLU but scales negative values by Alpha instead of
+// zeroing them. A zero Alpha defaults to 0.01.
+type LeakyReLU struct {
+	Alpha float64
+}
+
+func (LeakyReLU) Name() string { return "leaky_relu" }
+
+func (l LeakyReLU) alpha() float64 { return orDefault(l.Alpha, 0.01) }
+
+func (l LeakyReLU) Forward(z *matrix.Matrix) *matrix.Matrix {
+	alpha := l.alpha()
+	return z.Map(func(x float64) float64 {
+		if x > 0 {
+			return x
+		}
+		return alpha * x
+	})
+}
+
+func (l LeakyReLU) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	alpha := l.alpha()
+	return chain(z.Map(func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return alpha
+	}), grad)
+}
+
+// ELU is the exponential linear unit: values above zero pass through and values
+// below saturate smoothly towards -Alpha. A zero Alpha defaults to 1.
+type ELU struct {
+	Alpha float64
+}
+
+func (ELU) Name() string { return "elu" }
+
+func (e ELU) alpha() float64 { return orDefault(e.Alpha, 1) }
+
+func (e ELU) Forward(z *matrix.Matrix) *matrix.Matrix {
+	alpha := e.alpha()
+	return z.Map(func(x float64) float64 {
+		if x > 0 {
+			return x
+		}
+		return alpha * math.Expm1(x)
+	})
+}
+
+func (e ELU) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	alpha := e.alpha()
+	return chain(z.Map(func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return alpha * math.Exp(x)
+	}), grad)
+}
+
+// GELU is the Gaussian error linear unit, computed with the tanh approximation.
+type GELU struct{}
+
+// geluScale is sqrt(2/pi), used by the tanh approximation of GELU.
+var geluScale = math.Sqrt(2 / math.Pi)
+
+func (GELU) Name() string { return "gelu" }
+
+func (GELU) Forward(z *matrix.Matrix) *matrix.Matrix {
+	return z.Map(func(x float64) float64 {
+		return 0.5 * x * (1 + math.Tanh(geluScale*(x+0.044715*x*x*x)))
+	})
+}
+
+func (GELU) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	return chain(z.Map(func(x float64) float64 {
+		t := math.Tanh(geluScale * (x + 0.044715*x*x*x))
+		return 0.5*(1+t) + 0.5*x*(1-t*t)*geluScale*(1+3*0.044715*x*x)
+	}), grad)
+}
+
+// Linear leaves values untouched. It is the usual choice for regression outputs.
+type Linear struct{}
+
+func (Linear) Name() string { return "linear" }
+
+func (Linear) Forward(z *matrix.Matrix) *matrix.Matrix {
+	return z
+}
+
+func (Linear) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	return grad, nil
+}
+
+// Softmax turns every column into a probability distribution. The column maximum
+// is subtracted before exponentiating so large values cannot overflow.
+type Softmax struct{}
+
+func (Softmax) Name() string { return "softmax" }
+
+func (Softmax) Forward(z *matrix.Matrix) *matrix.Matrix {
+	z = z.Contiguous()
+	result := matrix.New(z.Row, z.Col)
+	for j := 0; j < z.Col; j++ {
+		max := math.Inf(-1)
+		for i := 0; i < z.Row; i++ {
+			max = math.Max(max, z.Matrix[i][j])
+		}
+
+		sum := 0.0
+		for i := 0; i < z.Row; i++ {
+			result.Matrix[i][j] = math.Exp(z.Matrix[i][j] - max)
+			sum += result.Matrix[i][j]
+		}
+		for i := 0; i < z.Row; i++ {
+			result.Matrix[i][j] /= sum
+		}
+	}
+	return result
+}
+
+// Backward multiplies grad by the softmax Jacobian of every column:
+// dz_i = a_i * (grad_i - sum_j a_j * grad_j).
+func (Softmax) Backward(z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	if a.Row != grad.Row || a.Col != grad.Col {
+		return nil, errors.ErrRowsColsMustEqual
+	}
+
+	a, grad = a.Contiguous(), grad.Contiguous()
+	result := matrix.New(a.Row, a.Col)
+	for j := 0; j < a.Col; j++ {
+		dot := 0.0
+		for i := 0; i < a.Row; i++ {
+			dot += a.Matrix[i][j] * grad.Matrix[i][j]
+		}
+		for i := 0; i < a.Row; i++ {
+			result.Matrix[i][j] = a.Matrix[i][j] * (grad.Matrix[i][j] - dot)
+		}
+	}
+	return result, nil
+}
+
+// chain multiplies derivative, a matrix the caller has just computed, by grad in
+// place and returns it.
+func chain(derivative, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	if err := derivative.HadProductInPlace(grad); err != nil {
+		return nil, err
+	}
+	return derivative, nil
+}
+
+// hadamardInto is implemented by backends, such as the cpu backend, that write the
+// element-wise product into an existing matrix.
+type hadamardInto interface {
+	HadamardInto(dst, a, b *matrix.Matrix) error
+}
+
+// hadamard returns the element-wise product of a and b computed by backend. Backends
+// implementing hadamardInto write it into dst, a matrix of their shape that may be
+// a, instead of allocating a new one.
+func hadamard(backend matrix.Backend, dst, a, b *matrix.Matrix) (*matrix.Matrix, error) {
+	into, ok := backend.(hadamardInto)
+	if !ok {
+		return backend.Hadamard(a, b)
+	}
+	if err := into.HadamardInto(dst, a, b); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -1,104 +1,172 @@
 package neural
 
 import (
+	"fmt"
+	"math/rand/v2"
 	"neuraln/matrix"
 )
 
-// backPropagate performs the backpropagation algorithm using Gradient Descent to adjust
-// the weights and biases of the neural network based on the provided inputs and target outputs.
-//
-// The function computes the error between the predicted outputs and the target outputs,
-// then propagates this error backward through the network to calculate gradients.
-// These gradients are used to update the weights and biases, minimizing the error.
+// update performs one training step: it clips the gradients and lets the optimizer
+// adjust the weights and biases of the neural network.
 //
 // Parameters:
-//   - inputs: A matrix representing the input values to the neural network.
-//   - targets: A matrix representing the target output values for the given inputs.
+//   - grads: The gradients of the step, e.g. from computeGradients.
+//   - learningRate: The learning rate passed to the optimizer.
+//   - clipping: How to clip the gradients before the update, nil to leave them as is.
 //
-// If any matrix operations fail, the function will return an error.
-func (neural *Neural) backPropagate(inputs *matrix.Matrix, targets *matrix.Matrix) error {
-	// Forward pass
-	hidden, err := neural.WeightIH.DotProduct(inputs)
-	if err != nil {
-		return err
-	}
-	hidden, err = hidden.AddFromMatrix(neural.BiasH)
-	if err != nil {
-		return err
-	}
-	hidden = hidden.Sigmoid()
-	outputs, err := neural.WeightHO.DotProduct(hidden)
-	if err != nil {
-		return err
+// Returns:
+//   - float64: The global L2 norm of the gradients before clipping.
+//   - error: An error if the gradients do not match the parameters, otherwise nil.
+func (neural *Neural) update(grads *Gradients, learningRate float64, clipping *Clipping) (float64, error) {
+	norm := clipping.clip(neural.parameters(grads))
+	if err := neural.applyGradients(grads, neural.optimizer(), learningRate); err != nil {
+		return 0, err
 	}
-	outputs, err = outputs.AddFromMatrix(neural.BiasO)
-	if err != nil {
-		return err
-	}
-	outputs = outputs.Sigmoid()
+	return norm, nil
+}
 
-	// Calculate output errors
-	outputErrors, err := targets.SubtractMatrix(outputs)
-	if err != nil {
-		return err
-	}
+// computeGradients runs the backpropagation algorithm for training without changing
+// the network, drawing the dropout masks from r.
+func (neural *Neural) computeGradients(inputs *matrix.Matrix, targets *matrix.Matrix, r *rand.Rand) (*Gradients, *matrix.Matrix, error) {
+	return neural.backPropagate(inputs, targets, neural.dropoutMasks(r, inputs.Col, nil), nil)
+}
 
-	// Calculate output gradient
-	outputGradients := outputs.DSigmoid()
-	outputGradients, err = outputGradients.HadProduct(outputErrors)
+// backPropagate runs the backpropagation algorithm without changing the network.
+//
+// The function computes the gradient of the network's loss with respect to the outputs,
+// then propagates it backward through the network, one layer at a time, to calculate
+// the gradients of every weight and bias. The losses average over the samples in the
+// columns of inputs, so the gradients are averaged over the batch as well. The L1 and
+// L2 penalties of the layers are added to both the loss and the gradients. The outputs
+// of every layer are multiplied by its dropout mask, if any, and BatchNorm layers use
+// the statistics of the batch, which are returned with the gradients. The gradients and intermediate matrices are kept in
+// ws, so the gradients are only valid until ws is used again.
+//
+// Returns:
+//   - *Gradients: The gradients of every layer and the loss of the network.
+//   - *matrix.Matrix: The outputs of the network for the given inputs.
+//   - error: An error if any matrix operation fails, otherwise nil.
+func (neural *Neural) backPropagate(inputs *matrix.Matrix, targets *matrix.Matrix, masks []*matrix.Matrix, ws *workspace) (*Gradients, *matrix.Matrix, error) {
+	// Forward pass
+	pass, err := neural.forward(inputs, true, masks, ws)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	outputGradients = outputGradients.ScalerMul(neural.LearningRate)
 
-	// Calculate delta for weights between hidden and output layers
-	hiddenTransposed := hidden.Transpose()
-	weightsHODelta, err := outputGradients.DotProduct(hiddenTransposed)
+	loss, err := neural.loss().Loss(pass.outputs(), targets)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	loss += neural.penalty()
 
-	// Adjust weights and biases for the output layer
-	neural.WeightHO, err = neural.WeightHO.AddFromMatrix(weightsHODelta)
+	// Calculate the gradient with respect to the weighted sums of the output layer
+	last := len(neural.Layers) - 1
+	deltas, fused, err := fusedGradient(neural.Layers[last].activation(), neural.loss(), pass.outputs(), targets)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	neural.BiasO, err = neural.BiasO.AddFromMatrix(outputGradients)
-	if err != nil {
-		return err
+	if !fused {
+		outputGradients, err := neural.loss().Gradient(pass.outputs(), targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		deltas, err = neural.Layers[last].deactivate(neural.compute(), pass.weighted[last], pass.outputs(), outputGradients)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	// Calculate hidden layer errors
-	weightHOTransposed := neural.WeightHO.Transpose()
-	hiddenErrors, err := weightHOTransposed.DotProduct(outputErrors)
-	if err != nil {
-		return err
-	}
+	grads := &Gradients{Layers: make([]LayerGradients, len(neural.Layers)), Loss: loss}
+	for i := last; i >= 0; i-- {
+		layer := neural.Layers[i]
+		var gammaGradients, betaGradients *matrix.Matrix
+		if norm := pass.norms[i]; norm != nil {
+			deltas, gammaGradients, betaGradients = layer.denormalize(norm, deltas)
+		}
 
-	// Calculate hidden gradient
-	hiddenGradients := hidden.DSigmoid()
-	hiddenGradients, err = hiddenGradients.HadProduct(hiddenErrors)
-	if err != nil {
-		return err
-	}
-	hiddenGradients = hiddenGradients.ScalerMul(neural.LearningRate)
+		// Calculate the gradient of the weights feeding into this layer
+		weightsGradients := ws.matrix(i, weightsBuffer, layer.Weights.Row, layer.Weights.Col)
+		err := neural.compute().Gemm(false, true, 1, deltas, pass.activations[i], 0, weightsGradients)
+		if err != nil {
+			return nil, nil, err
+		}
+		biasGradients := ws.matrix(i, biasBuffer, layer.Bias.Row, 1)
+		if err := deltas.SumColumnsInto(biasGradients); err != nil {
+			return nil, nil, err
+		}
 
-	// Calculate delta for weights between input and hidden layers
-	inputsTransposed := inputs.Transpose()
-	weightsIHDelta, err := hiddenGradients.DotProduct(inputsTransposed)
-	if err != nil {
-		return err
-	}
+		// Add the gradients of the layer's L1 and L2 penalties
+		layer.regularize(layer.Weights, weightsGradients)
+		if layer.RegularizeBias {
+			layer.regularize(layer.Bias, biasGradients)
+		}
+		grads.Layers[i] = LayerGradients{Weights: weightsGradients, Bias: biasGradients, Gamma: gammaGradients, Beta: betaGradients}
+		if norm := pass.norms[i]; norm != nil && norm.batch != nil {
+			if grads.statistics == nil {
+				grads.statistics = [][]*batchStatistics{make([]*batchStatistics, len(neural.Layers))}
+			}
+			grads.statistics[0][i] = norm.batch
+		}
 
-	// Adjust weights and biases for the hidden layer
-	neural.WeightIH, err = neural.WeightIH.AddFromMatrix(weightsIHDelta)
-	if err != nil {
-		return err
+		// Propagate the gradient to the previous layer
+		if i > 0 {
+			previousGradients := ws.matrix(i, previousBuffer, layer.Weights.Col, deltas.Col)
+			err := neural.compute().Gemm(true, false, 1, layer.Weights, deltas, 0, previousGradients)
+			if err != nil {
+				return nil, nil, err
+			}
+			// Only the outputs that survived dropout receive a gradient
+			if mask := pass.masks[i-1]; mask != nil {
+				if previousGradients, err = hadamard(neural.compute(), previousGradients, previousGradients, mask); err != nil {
+					return nil, nil, err
+				}
+			}
+			deltas, err = neural.Layers[i-1].deactivate(neural.compute(), pass.weighted[i-1], pass.activated[i-1], previousGradients)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 	}
-	neural.BiasH, err = neural.BiasH.AddFromMatrix(hiddenGradients)
-	if err != nil {
+
+	return grads, pass.outputs(), nil
+}
+
+// applyGradients applies the network's weight decay and then lets the optimizer move
+// every weight and bias against its gradient. Finally the running statistics of
+// BatchNorm layers move towards the batch statistics of the gradients, batch by
+// batch.
+func (neural *Neural) applyGradients(grads *Gradients, optimizer Optimizer, learningRate float64) error {
+	neural.decay(learningRate)
+	if err := optimizer.Step(neural.parameters(grads), learningRate); err != nil {
 		return err
 	}
 
+	for _, batch := range grads.statistics {
+		for i, statistics := range batch {
+			if norm, ok := neural.Layers[i].Normalization.(BatchNorm); ok && statistics != nil {
+				norm.track(neural.Layers[i], statistics)
+			}
+		}
+	}
 	return nil
 }
+
+// parameters pairs every weight and bias matrix of the network, and the Gamma and
+// Beta of normalized layers, with its gradient.
+// The names are stable across runs so optimizers can key their state on them.
+func (neural *Neural) parameters(grads *Gradients) []Parameter {
+	parameters := make([]Parameter, 0, 2*len(neural.Layers))
+	for i, layer := range neural.Layers {
+		parameters = append(parameters,
+			Parameter{Name: fmt.Sprintf("layers.%d.weights", i), Value: layer.Weights, Gradient: grads.Layers[i].Weights},
+			Parameter{Name: fmt.Sprintf("layers.%d.bias", i), Value: layer.Bias, Gradient: grads.Layers[i].Bias},
+		)
+		if layer.Normalization != nil {
+			parameters = append(parameters,
+				Parameter{Name: fmt.Sprintf("layers.%d.gamma", i), Value: layer.Gamma, Gradient: grads.Layers[i].Gamma},
+				Parameter{Name: fmt.Sprintf("layers.%d.beta", i), Value: layer.Beta, Gradient: grads.Layers[i].Beta},
+			)
+		}
+	}
+	return parameters
+}
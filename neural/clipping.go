@@ -0,0 +1,55 @@
+package neural
+
+import "math"
+
+// Clipping limits the gradients of a training step before the optimizer applies
+// them. Value clipping is applied first, then norm clipping.
+type Clipping struct {
+	// Value limits every gradient to [-Value, Value]. Zero disables it.
+	Value float64
+	// Norm rescales the gradients of all parameters together whenever their global
+	// L2 norm exceeds Norm, keeping their direction. Zero disables it.
+	Norm float64
+}
+
+// clip clips the gradients of the parameters in place.
+//
+// Returns:
+//   - float64: The global L2 norm of the gradients before clipping.
+func (c *Clipping) clip(parameters []Parameter) float64 {
+	norm := globalNorm(parameters)
+	if c == nil {
+		return norm
+	}
+
+	if c.Value > 0 {
+		for _, parameter := range parameters {
+			parameter.Gradient.MapInPlace(func(g float64) float64 {
+				return math.Max(-c.Value, math.Min(c.Value, g))
+			})
+		}
+	}
+
+	if c.Norm > 0 {
+		// Measure again, value clipping may already have shrunk the gradients
+		if clipped := globalNorm(parameters); clipped > c.Norm {
+			for _, parameter := range parameters {
+				scale(parameter.Gradient, c.Norm/clipped)
+			}
+		}
+	}
+	return norm
+}
+
+// globalNorm returns the L2 norm of the gradients of all parameters taken together.
+func globalNorm(parameters []Parameter) float64 {
+	total := 0.0
+	for _, parameter := range parameters {
+		for _, row := range parameter.Gradient.Contiguous().Matrix {
+			for _, g := range row {
+				total += g * g
+			}
+		}
+	}
+	return math.Sqrt(total)
+}
@@ -1,19 +1,87 @@
 package neural
 
 import (
+	"neuraln/errors"
 	"neuraln/matrix"
 )
 
+// Create builds a network with a single hidden layer. It is a shorthand for
+// CreateDeep with the sizes {inputNodes, hiddenNodes, outputNodes}, except that the
+// network minimizes BinaryCrossEntropy, which suits its Sigmoid output.
 func (neural *Neural) Create(inputNodes, hiddenNodes, outputNodes int) *Neural {
+	neural.build([]int{inputNodes, hiddenNodes, outputNodes})
+	neural.Loss = BinaryCrossEntropy{}
+	neural.initialize()
+	return neural
+}
+
+// CreateDeep builds a network from a list of layer sizes. The first entry is the
+// number of input nodes, the last one the number of output nodes and every entry
+// in between adds a hidden layer of that width, e.g. {784, 256, 128, 10}.
+//
+// Every layer uses the Sigmoid activation unless options select another one. Without
+// WithLoss the network minimizes MeanSquaredError. The weights are initialized
+// after all options have been applied, so WithSeed makes them reproducible.
+//
+// Returns:
+//   - *Neural: The initialized network.
+//   - error: An error if fewer than two sizes are given, any size is below one or an option fails.
+func (neural *Neural) CreateDeep(sizes []int, options ...Option) (*Neural, error) {
+	if len(sizes) < 2 {
+		return nil, errors.ErrInvalidLayerSizes
+	}
+	for _, size := range sizes {
+		if size < 1 {
+			return nil, errors.ErrInvalidLayerSizes
+		}
+	}
+
+	neural.build(sizes)
+	for _, option := range options {
+		if err := option(neural); err != nil {
+			return nil, err
+		}
+	}
+	neural.initialize()
+
+	return neural, nil
+}
 
-	neural.WeightIH = matrix.New(hiddenNodes, inputNodes).Randomize()
-	neural.WeightHO = matrix.New(outputNodes, hiddenNodes).Randomize()
-	neural.BiasH = matrix.New(hiddenNodes, 1).Randomize()
-	neural.BiasO = matrix.New(outputNodes, 1).Randomize()
+// build allocates one layer between every pair of consecutive sizes.
+func (neural *Neural) build(sizes []int) {
+	neural.Layers = make([]*Layer, len(sizes)-1)
+	for i := range neural.Layers {
+		neural.Layers[i] = &Layer{
+			Weights:    matrix.New(sizes[i+1], sizes[i]),
+			Bias:       matrix.New(sizes[i+1], 1),
+			Activation: Sigmoid{},
+		}
+	}
 
 	neural.LearningRate = 1
-	neural.InputNodes = inputNodes
-	neural.OutputNodes = outputNodes
+	neural.InputNodes = sizes[0]
+	neural.OutputNodes = sizes[len(sizes)-1]
+}
 
-	return neural
+// defaultInitializer is used for the weights and biases of layers created without
+// an initializer.
+var defaultInitializer = RandomUniform{Low: -1, High: 1}
+
+// initialize fills the weights and biases of every layer using the layer's
+// initializers and the network's random number generator.
+func (neural *Neural) initialize() {
+	for _, layer := range neural.Layers {
+		fanOut, fanIn := layer.Weights.Row, layer.Weights.Col
+
+		weights, bias := layer.weightsInitializer, layer.biasInitializer
+		if weights == nil {
+			weights = defaultInitializer
+		}
+		if bias == nil {
+			bias = defaultInitializer
+		}
+
+		layer.Weights = weights.Initialize(neural.random(), fanOut, fanIn, fanIn, fanOut)
+		layer.Bias = bias.Initialize(neural.random(), fanOut, 1, fanIn, fanOut)
+	}
 }
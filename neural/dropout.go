@@ -0,0 +1,35 @@
+package neural
+
+import (
+	"math/rand/v2"
+	"neuraln/matrix"
+)
+
+// dropoutMasks draws the inverted dropout masks of a training step on samples
+// columns: every output of a hidden layer with Dropout is kept with probability
+// 1 - Dropout and the survivors are scaled by 1 / (1 - Dropout), so the expected
+// activations match inference, where dropout is disabled. Layers without dropout
+// get a nil mask; a nil r disables dropout altogether.
+func (neural *Neural) dropoutMasks(r *rand.Rand, samples int, ws *workspace) []*matrix.Matrix {
+	masks := make([]*matrix.Matrix, len(neural.Layers))
+	if r == nil {
+		return masks
+	}
+
+	for i, layer := range neural.Layers[:len(neural.Layers)-1] {
+		if layer.Dropout <= 0 {
+			continue
+		}
+		keep := 1 - layer.Dropout
+		masks[i] = ws.matrix(i, maskBuffer, layer.Weights.Row, samples)
+		for _, row := range masks[i].Matrix {
+			for j := range row {
+				row[j] = 0
+				if r.Float64() < keep {
+					row[j] = 1 / keep
+				}
+			}
+		}
+	}
+	return masks
+}
@@ -0,0 +1,118 @@
+package neural
+
+import (
+	"math"
+	"neuraln/errors"
+	"strings"
+)
+
+// EarlyStopping ends training once the monitored value stops improving.
+type EarlyStopping struct {
+	// Monitor names the value to watch: "loss", "val_loss", the name of a metric or
+	// "val_" followed by the name of a metric. It defaults to "val_loss" when
+	// validation data is available and to "loss" otherwise.
+	Monitor string
+	// Maximize treats larger values as better, e.g. for accuracy.
+	Maximize bool
+	// MinDelta is the smallest change that counts as an improvement.
+	MinDelta float64
+	// Patience is the number of epochs without improvement after which training stops.
+	Patience int
+	// RestoreBestWeights resets the weights and biases to those of the best epoch
+	// before training returns.
+	RestoreBestWeights bool
+}
+
+// monitor returns the name of the monitored value.
+func (e *EarlyStopping) monitor(validated bool) string {
+	if e.Monitor != "" {
+		return e.Monitor
+	}
+	if validated {
+		return "val_loss"
+	}
+	return "loss"
+}
+
+// Value returns the loss or metric of the epoch with the given name, using the
+// naming of EarlyStopping.Monitor.
+func (log EpochLog) Value(name string) (float64, bool) {
+	switch name {
+	case "loss":
+		return log.Loss, true
+	case "val_loss":
+		return log.ValidationLoss, log.Validated
+	}
+
+	if metric, ok := strings.CutPrefix(name, "val_"); ok && log.Validated {
+		if value, ok := log.ValidationMetrics[metric]; ok {
+			return value, true
+		}
+	}
+	value, ok := log.Metrics[name]
+	return value, ok
+}
+
+// earlyStopper tracks the monitored value of a training run.
+type earlyStopper struct {
+	config  *EarlyStopping
+	monitor string
+	best    float64
+	wait    int
+	epoch   int
+	weights []*Layer
+}
+
+// newEarlyStopper returns a stopper for a training run with the given metrics, or
+// errors.ErrUnknownMonitor if its epochs will not have the monitored value.
+func newEarlyStopper(config *EarlyStopping, metrics []Metric, validated bool) (*earlyStopper, error) {
+	// An epoch log with every value of the run tells whether the monitor names one
+	values := make(map[string]float64, len(metrics))
+	for _, metric := range metrics {
+		values[metric.Name()] = 0
+	}
+	monitor := config.monitor(validated)
+	if _, ok := (EpochLog{Validated: validated, Metrics: values, ValidationMetrics: values}).Value(monitor); !ok {
+		return nil, errors.ErrUnknownMonitor
+	}
+
+	best := math.Inf(1)
+	if config.Maximize {
+		best = math.Inf(-1)
+	}
+	return &earlyStopper{config: config, monitor: monitor, best: best, epoch: -1}, nil
+}
+
+// observe records the epoch and reports whether training should stop. The weights
+// of the network are snapshotted whenever the epoch is the best so far.
+func (s *earlyStopper) observe(neural *Neural, log EpochLog) (bool, error) {
+	value, ok := log.Value(s.monitor)
+	if !ok {
+		return false, errors.ErrUnknownMonitor
+	}
+
+	improved := value < s.best-s.config.MinDelta
+	if s.config.Maximize {
+		improved = value > s.best+s.config.MinDelta
+	}
+
+	if improved {
+		s.best = value
+		s.epoch = log.Epoch
+		s.wait = 0
+		if s.config.RestoreBestWeights {
+			s.weights = neural.copyLayers()
+		}
+		return false, nil
+	}
+
+	s.wait++
+	return s.wait >= s.config.Patience, nil
+}
+
+// restore resets the network to the weights of the best epoch, if requested.
+func (s *earlyStopper) restore(neural *Neural) {
+	if s.config.RestoreBestWeights && s.weights != nil {
+		neural.Layers = s.weights
+	}
+}
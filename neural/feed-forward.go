@@ -23,27 +23,91 @@ func (neural *Neural) FeedForword(inputArray []float64) (*matrix.Matrix, error)
 	// Convert the input array to a matrix
 	inputs := matrix.NewFromArray(inputArray)
 
-	// Compute hidden layer activations
-	hidden, err := neural.WeightIH.DotProduct(inputs)
+	pass, err := neural.forward(inputs, false, nil, nil)
 	if err != nil {
 		return nil, err
 	}
-	hidden, err = hidden.AddFromMatrix(neural.BiasH)
-	if err != nil {
-		return nil, err
+
+	return pass.outputs(), nil
+}
+
+// pass holds the intermediate values of a forward pass that backpropagation needs.
+type pass struct {
+	// activations holds the inputs followed by the output of every layer, after dropout.
+	activations []*matrix.Matrix
+	// weighted holds the weighted sums of every layer before its activation, after
+	// normalization.
+	weighted []*matrix.Matrix
+	// norms holds the normalized values of every layer, nil for layers without one.
+	norms []*normalized
+	// activated holds the output of every layer's activation before dropout.
+	activated []*matrix.Matrix
+	// masks holds the dropout mask of every layer, nil where no dropout was applied.
+	masks []*matrix.Matrix
+}
+
+// outputs returns the activations of the last layer.
+func (p *pass) outputs() *matrix.Matrix {
+	return p.activations[len(p.activations)-1]
+}
+
+// forward propagates the inputs through every layer of the network. Every column
+// of inputs is one sample; the bias of each layer is added to all of them. In training
+// BatchNorm uses the statistics of the batch and the outputs of every layer are
+// multiplied by its dropout mask, if any; otherwise the network runs for inference.
+// The weighted sums and the outputs after dropout are kept in ws.
+//
+// Returns:
+//   - *pass: The weighted sums and activations of every layer.
+//   - error: ErrNoLayers if the network has no layers, an error if any matrix
+//     operation fails, otherwise nil.
+func (neural *Neural) forward(inputs *matrix.Matrix, training bool, masks []*matrix.Matrix, ws *workspace) (*pass, error) {
+	if len(neural.Layers) == 0 {
+		return nil, errors.ErrNoLayers
 	}
-	hidden = hidden.Sigmoid()
 
-	// Compute output layer activations
-	outputs, err := neural.WeightHO.DotProduct(hidden)
-	if err != nil {
-		return nil, err
+	p := &pass{
+		activations: make([]*matrix.Matrix, 0, len(neural.Layers)+1),
+		weighted:    make([]*matrix.Matrix, 0, len(neural.Layers)),
+		activated:   make([]*matrix.Matrix, 0, len(neural.Layers)),
+		norms:       make([]*normalized, len(neural.Layers)),
+		masks:       make([]*matrix.Matrix, len(neural.Layers)),
 	}
-	outputs, err = outputs.AddFromMatrix(neural.BiasO)
-	if err != nil {
-		return nil, err
+	p.activations = append(p.activations, inputs)
+
+	current := inputs
+	for i, layer := range neural.Layers {
+		// Start from the bias of every sample and add the weighted inputs in place
+		weighted := ws.matrix(i, weightedBuffer, layer.Bias.Row, current.Col)
+		broadcast(weighted, layer.Bias)
+		err := neural.compute().Gemm(false, false, 1, layer.Weights, current, 1, weighted)
+		if err != nil {
+			return nil, err
+		}
+		weighted, p.norms[i] = layer.normalize(weighted, training)
+		current = layer.activate(neural.compute(), weighted)
+		p.weighted = append(p.weighted, weighted)
+		p.activated = append(p.activated, current)
+		if training && masks[i] != nil {
+			dropped := ws.matrix(i, droppedBuffer, current.Row, current.Col)
+			if current, err = hadamard(neural.compute(), dropped, current, masks[i]); err != nil {
+				return nil, err
+			}
+			p.masks[i] = masks[i]
+		}
+		p.activations = append(p.activations, current)
 	}
-	outputs = outputs.Sigmoid()
 
-	return outputs, nil
+	return p, nil
+}
+
+// broadcast fills every column of dst, a dense matrix, with the column vector bias.
+func broadcast(dst, bias *matrix.Matrix) {
+	for i := 0; i < bias.Row; i++ {
+		v := bias.At(i, 0)
+		row := dst.Data[i*dst.Col : (i+1)*dst.Col]
+		for j := range row {
+			row[j] = v
+		}
+	}
 }
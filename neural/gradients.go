@@ -0,0 +1,156 @@
+package neural
+
+import (
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Gradients holds the gradient of the loss with respect to every parameter of the
+// network, mirroring Neural.Layers.
+type Gradients struct {
+	Layers []LayerGradients
+	// Loss is the loss of the network, including the L1 and L2 penalties, for the
+	// samples the gradients were computed on.
+	Loss float64
+
+	// statistics holds, for every batch the gradients were computed on, the batch
+	// statistics of every layer, nil for layers without BatchNorm.
+	statistics [][]*batchStatistics
+}
+
+// LayerGradients holds the gradients of a single layer, with the same shapes as the
+// layer's parameters. Gamma and Beta are nil for layers without a normalization.
+type LayerGradients struct {
+	Weights *matrix.Matrix
+	Bias    *matrix.Matrix
+	Gamma   *matrix.Matrix
+	Beta    *matrix.Matrix
+}
+
+// Gradients computes the gradients of the loss for the provided samples without
+// changing the network. The network runs as in training: dropout masks are drawn
+// and BatchNorm layers use the statistics of the samples, which ApplyGradients
+// then moves their running statistics towards. The masks come from a random
+// number generator of their own, seeded by WithSeed, so Gradients leaves the
+// network's generator as it is.
+//
+// Together with ApplyGradients it allows custom training loops, e.g. accumulating
+// the gradients of several batches with Add before a single update.
+//
+// Parameters:
+//   - inputArray: The input samples, processed as a single batch.
+//   - targetArray: The target outputs of every sample.
+//
+// Returns:
+//   - *Gradients: The gradients, averaged over the samples, and the loss.
+//   - error: An error if the input and target arrays do not match the expected dimensions,
+//     or if a network with BatchNorm layers gets a single sample, otherwise nil.
+func (neural *Neural) Gradients(inputArray, targetArray [][]float64) (*Gradients, error) {
+	if err := neural.validate(inputArray, targetArray); err != nil {
+		return nil, err
+	}
+	// BatchNorm normalizes a single sample to zero
+	if neural.batchNormalized() && len(inputArray) < 2 {
+		return nil, errors.ErrBatchNormBatchSize
+	}
+
+	grads, _, err := neural.computeGradients(matrix.NewFromColumns(inputArray), matrix.NewFromColumns(targetArray), neural.dropoutRandom())
+	return grads, err
+}
+
+// ApplyGradients performs a single update of the network with the given gradients,
+// using the network's LearningRate and WeightDecay. The running statistics of
+// BatchNorm layers move towards the statistics of every batch the gradients were
+// computed on.
+//
+// Parameters:
+//   - grads: Gradients matching the layers of the network, e.g. from Gradients.
+//   - optimizer: The optimizer that applies the gradients. When nil, the network's
+//     own optimizer is used.
+//
+// Returns:
+//   - error: An error if the gradients do not match the parameters of the network, otherwise nil.
+func (neural *Neural) ApplyGradients(grads *Gradients, optimizer Optimizer) error {
+	if err := neural.check(grads); err != nil {
+		return err
+	}
+	if optimizer == nil {
+		optimizer = neural.optimizer()
+	}
+	return neural.applyGradients(grads, optimizer, neural.LearningRate)
+}
+
+// check verifies that grads holds a gradient of the right shape for every
+// parameter of the network.
+func (neural *Neural) check(grads *Gradients) error {
+	if grads == nil || len(grads.Layers) != len(neural.Layers) {
+		return errors.ErrGradientsMismatch
+	}
+	for i, layer := range neural.Layers {
+		pairs := [][2]*matrix.Matrix{
+			{layer.Weights, grads.Layers[i].Weights},
+			{layer.Bias, grads.Layers[i].Bias},
+		}
+		if layer.Normalization != nil {
+			pairs = append(pairs, [2]*matrix.Matrix{layer.Gamma, grads.Layers[i].Gamma}, [2]*matrix.Matrix{layer.Beta, grads.Layers[i].Beta})
+		}
+		for _, pair := range pairs {
+			if pair[1] == nil || pair[0].Row != pair[1].Row || pair[0].Col != pair[1].Col {
+				return errors.ErrGradientsMismatch
+			}
+		}
+	}
+	return nil
+}
+
+// Add adds other to the gradients, in place, e.g. to accumulate the gradients of
+// several batches or to combine several losses. The losses are added as well.
+func (g *Gradients) Add(other *Gradients) error {
+	if other == nil || len(g.Layers) != len(other.Layers) {
+		return errors.ErrGradientsMismatch
+	}
+	for i := range g.Layers {
+		ours, theirs := g.Layers[i].matrices(), other.Layers[i].matrices()
+		for j := range ours {
+			if (ours[j] == nil) != (theirs[j] == nil) {
+				return errors.ErrGradientsMismatch
+			}
+			if ours[j] != nil && (ours[j].Row != theirs[j].Row || ours[j].Col != theirs[j].Col) {
+				return errors.ErrGradientsMismatch
+			}
+		}
+	}
+
+	for i := range g.Layers {
+		ours, theirs := g.Layers[i].matrices(), other.Layers[i].matrices()
+		for j, m := range ours {
+			if m == nil {
+				continue
+			}
+			if err := m.AddInPlace(theirs[j]); err != nil {
+				return err
+			}
+		}
+	}
+	g.Loss += other.Loss
+	g.statistics = append(g.statistics, other.statistics...)
+	return nil
+}
+
+// Scale multiplies the gradients and the loss by factor, in place, e.g. to average
+// accumulated gradients or to weight one of several losses.
+func (g *Gradients) Scale(factor float64) {
+	for _, layer := range g.Layers {
+		for _, m := range layer.matrices() {
+			if m != nil {
+				scale(m, factor)
+			}
+		}
+	}
+	g.Loss *= factor
+}
+
+// matrices returns the gradients of the layer in a fixed order, including nil ones.
+func (l LayerGradients) matrices() []*matrix.Matrix {
+	return []*matrix.Matrix{l.Weights, l.Bias, l.Gamma, l.Beta}
+}
@@ -0,0 +1,88 @@
+package neural
+
+// EpochLog summarizes one epoch of training.
+type EpochLog struct {
+	// Epoch is zero-based.
+	Epoch int
+	// Loss is the training loss averaged over the batches of the epoch.
+	Loss float64
+	// Metrics holds the training metrics averaged over the batches of the epoch.
+	Metrics map[string]float64
+	// Validated reports whether validation data was given. ValidationLoss and
+	// ValidationMetrics are measured after the last step of the epoch.
+	Validated         bool
+	ValidationLoss    float64
+	ValidationMetrics map[string]float64
+	// LearningRate is the rate used by the last step of the epoch.
+	LearningRate float64
+}
+
+// BatchLog summarizes one training step.
+type BatchLog struct {
+	// Epoch and Batch are zero-based, Step counts the steps since training started.
+	// With AccumulationSteps, Batch counts the steps of the epoch, not the batches.
+	Epoch int
+	Batch int
+	Step  int
+	// Loss is the loss of the samples of the step before the update.
+	Loss         float64
+	LearningRate float64
+	// GradientNorm is the global L2 norm of the gradients of the step before clipping.
+	GradientNorm float64
+}
+
+// Callback is notified during training. Either function may be nil. Returning
+// errors.ErrStopTraining, or an error wrapping it, ends training after the current
+// step without an error; any other error aborts training and is returned by
+// TrainWithOptions.
+type Callback struct {
+	OnBatchEnd func(log BatchLog) error
+	OnEpochEnd func(log EpochLog) error
+}
+
+// History records every completed epoch of a training run.
+type History struct {
+	Epochs []EpochLog
+	// StoppedEarly reports whether a callback or EarlyStopping ended training
+	// before the last epoch.
+	StoppedEarly bool
+	// BestEpoch is the epoch with the best monitored value when EarlyStopping is
+	// used, and -1 otherwise.
+	BestEpoch int
+}
+
+// Loss returns the training loss of every epoch.
+func (h *History) Loss() []float64 {
+	losses := make([]float64, len(h.Epochs))
+	for i, epoch := range h.Epochs {
+		losses[i] = epoch.Loss
+	}
+	return losses
+}
+
+// ValidationLoss returns the validation loss of every epoch.
+func (h *History) ValidationLoss() []float64 {
+	losses := make([]float64, len(h.Epochs))
+	for i, epoch := range h.Epochs {
+		losses[i] = epoch.ValidationLoss
+	}
+	return losses
+}
+
+// Metric returns the named training metric of every epoch.
+func (h *History) Metric(name string) []float64 {
+	values := make([]float64, len(h.Epochs))
+	for i, epoch := range h.Epochs {
+		values[i] = epoch.Metrics[name]
+	}
+	return values
+}
+
+// ValidationMetric returns the named validation metric of every epoch.
+func (h *History) ValidationMetric(name string) []float64 {
+	values := make([]float64, len(h.Epochs))
+	for i, epoch := range h.Epochs {
+		values[i] = epoch.ValidationMetrics[name]
+	}
+	return values
+}
@@ -0,0 +1,151 @@
+package neural
+
+import (
+	"math"
+	"math/rand/v2"
+	"neuraln/matrix"
+)
+
+// Initializer draws the initial values of a rows x cols parameter of a layer with
+// fanIn inputs and fanOut nodes. For weights rows is fanOut and cols is fanIn.
+type Initializer interface {
+	Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix
+}
+
+// RandomUniform draws values uniformly from [Low, High) regardless of the layer
+// size. RandomUniform{Low: -1, High: 1} is used for layers created without an
+// initializer.
+type RandomUniform struct {
+	Low  float64
+	High float64
+}
+
+func (i RandomUniform) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return matrix.New(rows, cols).Fill(r, matrix.Uniform{Low: i.Low, High: i.High})
+}
+
+// RandomNormal draws values from a normal distribution regardless of the layer size.
+type RandomNormal struct {
+	Mean   float64
+	StdDev float64
+}
+
+func (i RandomNormal) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return matrix.New(rows, cols).Fill(r, matrix.Normal{Mean: i.Mean, StdDev: i.StdDev})
+}
+
+// scaled draws values with variance scale/fan, either uniformly or from a normal
+// distribution.
+func scaled(r *rand.Rand, rows, cols int, scale float64, fan int, normal bool) *matrix.Matrix {
+	variance := scale / float64(fan)
+	if normal {
+		return matrix.New(rows, cols).Fill(r, matrix.Normal{StdDev: math.Sqrt(variance)})
+	}
+	limit := math.Sqrt(3 * variance)
+	return matrix.New(rows, cols).Fill(r, matrix.Uniform{Low: -limit, High: limit})
+}
+
+// XavierUniform (Glorot) draws from [-limit, limit) with limit = sqrt(6 / (fanIn + fanOut)).
+// It suits sigmoid and tanh layers.
+type XavierUniform struct{}
+
+func (XavierUniform) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 2, fanIn+fanOut, false)
+}
+
+// XavierNormal (Glorot) draws from a normal distribution with standard deviation
+// sqrt(2 / (fanIn + fanOut)).
+type XavierNormal struct{}
+
+func (XavierNormal) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 2, fanIn+fanOut, true)
+}
+
+// HeUniform (Kaiming) draws from [-limit, limit) with limit = sqrt(6 / fanIn). It
+// suits ReLU layers.
+type HeUniform struct{}
+
+func (HeUniform) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 2, fanIn, false)
+}
+
+// HeNormal (Kaiming) draws from a normal distribution with standard deviation
+// sqrt(2 / fanIn).
+type HeNormal struct{}
+
+func (HeNormal) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 2, fanIn, true)
+}
+
+// LeCunUniform draws from [-limit, limit) with limit = sqrt(3 / fanIn).
+type LeCunUniform struct{}
+
+func (LeCunUniform) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 1, fanIn, false)
+}
+
+// LeCunNormal draws from a normal distribution with standard deviation sqrt(1 / fanIn).
+type LeCunNormal struct{}
+
+func (LeCunNormal) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return scaled(r, rows, cols, 1, fanIn, true)
+}
+
+// Orthogonal draws a matrix whose rows or columns, whichever are fewer, are
+// orthonormal, scaled by Gain. A zero Gain defaults to 1.
+type Orthogonal struct {
+	Gain float64
+}
+
+func (i Orthogonal) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	gain := orDefault(i.Gain, 1)
+
+	// Orthonormalize the shorter side of a normal matrix with Gram-Schmidt
+	transposed := rows < cols
+	if transposed {
+		rows, cols = cols, rows
+	}
+	m := matrix.New(rows, cols).Fill(r, matrix.Normal{StdDev: 1})
+	for j := 0; j < cols; j++ {
+		for k := 0; k < j; k++ {
+			dot := 0.0
+			for i := 0; i < rows; i++ {
+				dot += m.Matrix[i][j] * m.Matrix[i][k]
+			}
+			for i := 0; i < rows; i++ {
+				m.Matrix[i][j] -= dot * m.Matrix[i][k]
+			}
+		}
+
+		norm := 0.0
+		for i := 0; i < rows; i++ {
+			norm += m.Matrix[i][j] * m.Matrix[i][j]
+		}
+		norm = math.Sqrt(norm)
+		for i := 0; i < rows; i++ {
+			m.Matrix[i][j] /= norm
+		}
+	}
+
+	m.ScalerMulInPlace(gain)
+	if transposed {
+		return m.T().Contiguous()
+	}
+	return m
+}
+
+// Zeros initializes every value to zero, the usual choice for biases.
+type Zeros struct{}
+
+func (Zeros) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return matrix.New(rows, cols)
+}
+
+// Constant initializes every value to Value.
+type Constant struct {
+	Value float64
+}
+
+func (i Constant) Initialize(r *rand.Rand, rows, cols, fanIn, fanOut int) *matrix.Matrix {
+	return matrix.New(rows, cols).Map(func(float64) float64 { return i.Value })
+}
@@ -0,0 +1,206 @@
+package neural
+
+import (
+	"math"
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Loss measures how far the outputs of the network are from the targets. Every
+// column of outputs and targets holds one sample and losses are averaged over
+// the samples.
+type Loss interface {
+	// Name identifies the loss in exported models.
+	Name() string
+	// Loss returns the loss averaged over the samples.
+	Loss(outputs, targets *matrix.Matrix) (float64, error)
+	// Gradient returns the gradient of the averaged loss with respect to outputs.
+	Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error)
+}
+
+var losses = newRegistry[Loss](errors.ErrUnknownLoss)
+
+func init() {
+	for _, loss := range []Loss{
+		MeanSquaredError{}, MeanAbsoluteError{}, Huber{}, BinaryCrossEntropy{}, CategoricalCrossEntropy{},
+	} {
+		losses.register(loss.Name(), loss)
+	}
+}
+
+// RegisterLoss makes a custom loss available to ImportJSON. Models using a loss
+// that was not registered cannot be imported.
+func RegisterLoss(loss Loss) {
+	losses.register(loss.Name(), loss)
+}
+
+// epsilon keeps the cross-entropy losses away from log(0) and division by zero.
+const epsilon = 1e-12
+
+// reduce sums f over every element of outputs and targets and divides by the
+// number of samples.
+func reduce(outputs, targets *matrix.Matrix, f func(a, y float64) float64) (float64, error) {
+	if outputs.Row != targets.Row || outputs.Col != targets.Col {
+		return 0, errors.ErrOutputNodesMismatch
+	}
+
+	outputs, targets = outputs.Contiguous(), targets.Contiguous()
+	sum := 0.0
+	for i := 0; i < outputs.Row; i++ {
+		for j := 0; j < outputs.Col; j++ {
+			sum += f(outputs.Matrix[i][j], targets.Matrix[i][j])
+		}
+	}
+	return sum / float64(outputs.Col), nil
+}
+
+// derive applies f to every element of outputs and targets and divides the
+// result by the number of samples.
+func derive(outputs, targets *matrix.Matrix, f func(a, y float64) float64) (*matrix.Matrix, error) {
+	if outputs.Row != targets.Row || outputs.Col != targets.Col {
+		return nil, errors.ErrOutputNodesMismatch
+	}
+
+	outputs, targets = outputs.Contiguous(), targets.Contiguous()
+	samples := float64(outputs.Col)
+	result := matrix.New(outputs.Row, outputs.Col)
+	for i := 0; i < outputs.Row; i++ {
+		for j := 0; j < outputs.Col; j++ {
+			result.Matrix[i][j] = f(outputs.Matrix[i][j], targets.Matrix[i][j]) / samples
+		}
+	}
+	return result, nil
+}
+
+// clamp keeps probabilities inside [epsilon, 1-epsilon].
+func clamp(p float64) float64 {
+	return math.Min(math.Max(p, epsilon), 1-epsilon)
+}
+
+// MeanSquaredError is half the squared error summed over the outputs. The factor
+// of one half makes its gradient simply outputs - targets.
+type MeanSquaredError struct{}
+
+func (MeanSquaredError) Name() string { return "mse" }
+
+func (MeanSquaredError) Loss(outputs, targets *matrix.Matrix) (float64, error) {
+	return reduce(outputs, targets, func(a, y float64) float64 { return 0.5 * (a - y) * (a - y) })
+}
+
+func (MeanSquaredError) Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error) {
+	return derive(outputs, targets, func(a, y float64) float64 { return a - y })
+}
+
+// MeanAbsoluteError is the absolute error summed over the outputs.
+type MeanAbsoluteError struct{}
+
+func (MeanAbsoluteError) Name() string { return "mae" }
+
+func (MeanAbsoluteError) Loss(outputs, targets *matrix.Matrix) (float64, error) {
+	return reduce(outputs, targets, func(a, y float64) float64 { return math.Abs(a - y) })
+}
+
+func (MeanAbsoluteError) Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error) {
+	return derive(outputs, targets, func(a, y float64) float64 {
+		switch {
+		case a > y:
+			return 1
+		case a < y:
+			return -1
+		}
+		return 0
+	})
+}
+
+// Huber is quadratic for errors up to Delta and linear beyond it, which makes it
+// robust to outliers. A zero Delta defaults to 1.
+type Huber struct {
+	Delta float64
+}
+
+func (Huber) Name() string { return "huber" }
+
+func (h Huber) delta() float64 {
+	if h.Delta == 0 {
+		return 1
+	}
+	return h.Delta
+}
+
+func (h Huber) Loss(outputs, targets *matrix.Matrix) (float64, error) {
+	delta := h.delta()
+	return reduce(outputs, targets, func(a, y float64) float64 {
+		diff := math.Abs(a - y)
+		if diff <= delta {
+			return 0.5 * diff * diff
+		}
+		return delta * (diff - 0.5*delta)
+	})
+}
+
+func (h Huber) Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error) {
+	delta := h.delta()
+	return derive(outputs, targets, func(a, y float64) float64 {
+		return math.Max(-delta, math.Min(delta, a-y))
+	})
+}
+
+// BinaryCrossEntropy is the loss for independent yes/no outputs in (0, 1),
+// usually produced by a Sigmoid output layer.
+type BinaryCrossEntropy struct{}
+
+func (BinaryCrossEntropy) Name() string { return "binary_cross_entropy" }
+
+func (BinaryCrossEntropy) Loss(outputs, targets *matrix.Matrix) (float64, error) {
+	return reduce(outputs, targets, func(a, y float64) float64 {
+		a = clamp(a)
+		return -(y*math.Log(a) + (1-y)*math.Log(1-a))
+	})
+}
+
+func (BinaryCrossEntropy) Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error) {
+	return derive(outputs, targets, func(a, y float64) float64 {
+		a = clamp(a)
+		return (a - y) / (a * (1 - a))
+	})
+}
+
+// CategoricalCrossEntropy is the loss for one-hot targets, usually paired with a
+// Softmax output layer.
+type CategoricalCrossEntropy struct{}
+
+func (CategoricalCrossEntropy) Name() string { return "categorical_cross_entropy" }
+
+func (CategoricalCrossEntropy) Loss(outputs, targets *matrix.Matrix) (float64, error) {
+	return reduce(outputs, targets, func(a, y float64) float64 {
+		return -y * math.Log(clamp(a))
+	})
+}
+
+func (CategoricalCrossEntropy) Gradient(outputs, targets *matrix.Matrix) (*matrix.Matrix, error) {
+	return derive(outputs, targets, func(a, y float64) float64 {
+		return -y / clamp(a)
+	})
+}
+
+// fusedGradient returns the gradient with respect to the weighted sums of the
+// output layer when the loss and the output activation cancel out. Softmax with
+// categorical cross-entropy and Sigmoid with binary cross-entropy both reduce to
+// outputs - targets, which avoids dividing by probabilities close to zero.
+func fusedGradient(activation Activation, loss Loss, outputs, targets *matrix.Matrix) (*matrix.Matrix, bool, error) {
+	switch activation.(type) {
+	case Softmax:
+		if _, ok := loss.(CategoricalCrossEntropy); !ok {
+			return nil, false, nil
+		}
+	case Sigmoid:
+		if _, ok := loss.(BinaryCrossEntropy); !ok {
+			return nil, false, nil
+		}
+	default:
+		return nil, false, nil
+	}
+
+	gradient, err := derive(outputs, targets, func(a, y float64) float64 { return a - y })
+	return gradient, true, err
+}
@@ -0,0 +1,70 @@
+package neural
+
+import (
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Metric scores the outputs of the network against the targets. Like losses,
+// metrics receive one sample per column and average over the samples.
+type Metric interface {
+	// Name is the key of the metric in EpochLog.Metrics.
+	Name() string
+	Measure(outputs, targets *matrix.Matrix) (float64, error)
+}
+
+// Accuracy is the fraction of samples predicted correctly. Networks with a single
+// output count a sample as correct when output and target fall on the same side of
+// Threshold, which defaults to 0.5. Networks with several outputs compare the index
+// of the largest output with the index of the largest target.
+type Accuracy struct {
+	Threshold float64
+}
+
+func (Accuracy) Name() string { return "accuracy" }
+
+func (a Accuracy) Measure(outputs, targets *matrix.Matrix) (float64, error) {
+	if outputs.Row != targets.Row || outputs.Col != targets.Col {
+		return 0, errors.ErrOutputNodesMismatch
+	}
+
+	outputs, targets = outputs.Contiguous(), targets.Contiguous()
+	threshold := orDefault(a.Threshold, 0.5)
+	correct := 0
+	for j := 0; j < outputs.Col; j++ {
+		if outputs.Row == 1 {
+			if (outputs.Matrix[0][j] >= threshold) == (targets.Matrix[0][j] >= threshold) {
+				correct++
+			}
+			continue
+		}
+
+		if argmax(outputs, j) == argmax(targets, j) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(outputs.Col), nil
+}
+
+// argmax returns the row of the largest value in column j.
+func argmax(m *matrix.Matrix, j int) int {
+	best := 0
+	for i := 1; i < m.Row; i++ {
+		if m.Matrix[i][j] > m.Matrix[best][j] {
+			best = i
+		}
+	}
+	return best
+}
+
+// LossMetric tracks a loss as a metric, e.g. LossMetric{MeanAbsoluteError{}} to
+// report the absolute error of a network trained on the squared error.
+type LossMetric struct {
+	Loss Loss
+}
+
+func (m LossMetric) Name() string { return m.Loss.Name() }
+
+func (m LossMetric) Measure(outputs, targets *matrix.Matrix) (float64, error) {
+	return m.Loss.Loss(outputs, targets)
+}
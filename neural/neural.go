@@ -2,18 +2,290 @@ package neural
 
 import (
 	"encoding/json"
-	"log"
+	"math/rand/v2"
 	"neuraln/matrix"
 )
 
+// Layer is a fully connected layer. Weights maps the activations of the previous
+// layer onto this layer's nodes, Bias is added to every node and Activation is
+// applied to the result.
+type Layer struct {
+	Weights    *matrix.Matrix
+	Bias       *matrix.Matrix
+	Activation Activation
+	// L1 and L2 add L1 * sum(|w|) + L2 * sum(w^2) over the weights of the layer to
+	// the loss. Biases are only penalized when RegularizeBias is set.
+	L1             float64
+	L2             float64
+	RegularizeBias bool
+	// Normalization normalizes the weighted sums of the layer before Activation.
+	// Gamma and Beta scale and shift the normalized values and are trained with the
+	// weights; RunningMean and RunningVariance are the statistics BatchNorm uses at
+	// inference. Use WithNormalization to set them up together.
+	Normalization   Normalization
+	Gamma           *matrix.Matrix
+	Beta            *matrix.Matrix
+	RunningMean     *matrix.Matrix
+	RunningVariance *matrix.Matrix
+	// Dropout is the probability of zeroing each output of the layer during
+	// training. It is ignored by FeedForword and Evaluate.
+	Dropout float64
+
+	// weightsInitializer and biasInitializer are used once, when the network is created.
+	weightsInitializer Initializer
+	biasInitializer    Initializer
+}
+
 type Neural struct {
 	InputNodes   int
 	OutputNodes  int
-	WeightIH     *matrix.Matrix
-	WeightHO     *matrix.Matrix
-	BiasH        *matrix.Matrix
-	BiasO        *matrix.Matrix
+	Layers       []*Layer
 	LearningRate float64
+	// Loss is minimized by Train. When nil, MeanSquaredError is used.
+	Loss Loss
+	// Optimizer applies the gradients computed by Train. When nil, plain gradient
+	// descent is used.
+	Optimizer Optimizer
+	// WeightDecay shrinks the weights by LearningRate * WeightDecay before every
+	// optimizer step, independently of the loss and of the optimizer. Biases decay
+	// only in layers with RegularizeBias.
+	WeightDecay float64
+
+	// rng drives weight initialization, shuffling and dropout.
+	rng *rand.Rand
+	// dropoutRng draws the dropout masks of Gradients, which leaves rng as it is.
+	dropoutRng *rand.Rand
+	// backend runs the backend operations of the network. When nil, the
+	// process-wide matrix.DefaultBackend is used.
+	backend matrix.Backend
+}
+
+// random returns the network's random number generator, seeding a new one from
+// the global source if the network has none yet.
+func (n *Neural) random() *rand.Rand {
+	if n.rng == nil {
+		n.rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return n.rng
+}
+
+// dropoutRandom returns the random number generator of the dropout masks of
+// Gradients, seeding a new one from the global source if the network has none yet.
+func (n *Neural) dropoutRandom() *rand.Rand {
+	if n.dropoutRng == nil {
+		n.dropoutRng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return n.dropoutRng
+}
+
+// compute returns the backend that runs the network's backend operations.
+func (n *Neural) compute() matrix.Backend {
+	if n.backend == nil {
+		return matrix.DefaultBackend()
+	}
+	return n.backend
+}
+
+// optimizer returns the network's optimizer, falling back to plain SGD.
+func (n *Neural) optimizer() Optimizer {
+	if n.Optimizer == nil {
+		return &SGD{}
+	}
+	return n.Optimizer
+}
+
+// loss returns the network's loss, falling back to MeanSquaredError.
+func (n *Neural) loss() Loss {
+	if n.Loss == nil {
+		return MeanSquaredError{}
+	}
+	return n.Loss
+}
+
+// MarshalJSON stores the loss and the optimizer, including its state, by their
+// registered names next to the layers. A nil Loss or Optimizer is stored as null,
+// so the model keeps using the default.
+func (n *Neural) MarshalJSON() ([]byte, error) {
+	type plain Neural
+	name := ""
+	if n.Loss != nil {
+		name = n.Loss.Name()
+	}
+	loss, err := losses.encode(name, n.Loss)
+	if err != nil {
+		return nil, err
+	}
+	name = ""
+	if n.Optimizer != nil {
+		name = n.Optimizer.Name()
+	}
+	optimizer, err := optimizers.encode(name, n.Optimizer)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		*plain
+		Loss      json.RawMessage
+		Optimizer json.RawMessage
+	}{(*plain)(n), loss, optimizer})
+}
+
+// UnmarshalJSON restores a network written by MarshalJSON. Models exported before
+// networks had layers, with a single Sigmoid hidden layer stored in WeightIH,
+// WeightHO, BiasH and BiasO, are converted into the equivalent two layers and,
+// like the networks built by Create, minimize BinaryCrossEntropy.
+func (n *Neural) UnmarshalJSON(data []byte) error {
+	type plain Neural
+	aux := struct {
+		*plain
+		Loss      json.RawMessage
+		Optimizer json.RawMessage
+		WeightIH  *matrix.Matrix
+		WeightHO  *matrix.Matrix
+		BiasH     *matrix.Matrix
+		BiasO     *matrix.Matrix
+	}{plain: (*plain)(n)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	legacy := len(n.Layers) == 0 && aux.WeightIH != nil && aux.WeightHO != nil && aux.BiasH != nil && aux.BiasO != nil
+	if legacy {
+		n.Layers = []*Layer{
+			{Weights: aux.WeightIH, Bias: aux.BiasH, Activation: Sigmoid{}},
+			{Weights: aux.WeightHO, Bias: aux.BiasO, Activation: Sigmoid{}},
+		}
+	}
+
+	loss, err := losses.decode(aux.Loss)
+	if err != nil {
+		return err
+	}
+	optimizer, err := optimizers.decode(aux.Optimizer)
+	if err != nil {
+		return err
+	}
+	if legacy && loss == nil {
+		loss = BinaryCrossEntropy{}
+	}
+	n.Loss = loss
+	n.Optimizer = optimizer
+	return nil
+}
+
+// activation returns the layer's activation, falling back to Sigmoid for layers
+// that were created without one.
+func (l *Layer) activation() Activation {
+	if l.Activation == nil {
+		return Sigmoid{}
+	}
+	return l.Activation
+}
+
+// activate applies the layer's activation to z, running it on backend when the
+// activation supports it.
+func (l *Layer) activate(backend matrix.Backend, z *matrix.Matrix) *matrix.Matrix {
+	if activation, ok := l.activation().(accelerated); ok {
+		return activation.forwardOn(backend, z)
+	}
+	return l.activation().Forward(z)
+}
+
+// deactivate returns the gradient with respect to the layer's weighted sums z,
+// running the activation's Backward on backend when it supports it.
+func (l *Layer) deactivate(backend matrix.Backend, z, a, grad *matrix.Matrix) (*matrix.Matrix, error) {
+	if activation, ok := l.activation().(accelerated); ok {
+		return activation.backwardOn(backend, z, a, grad)
+	}
+	return l.activation().Backward(z, a, grad)
+}
+
+// Snapshot returns a copy of the network for inference. The layers are deep copies
+// and the loss is shared, while the optimizer and its state and the random number
+// generator are left out, so the snapshot can predict while the network keeps
+// training. Training the snapshot starts with a fresh optimizer.
+func (n *Neural) Snapshot() *Neural {
+	return &Neural{
+		InputNodes:   n.InputNodes,
+		OutputNodes:  n.OutputNodes,
+		Layers:       n.copyLayers(),
+		LearningRate: n.LearningRate,
+		Loss:         n.Loss,
+		WeightDecay:  n.WeightDecay,
+		backend:      n.backend,
+	}
+}
+
+// copyLayers returns a deep copy of the layers of the network.
+func (n *Neural) copyLayers() []*Layer {
+	layers := make([]*Layer, len(n.Layers))
+	for i, layer := range n.Layers {
+		copied := *layer
+		copied.Weights = layer.Weights.Copy()
+		copied.Bias = layer.Bias.Copy()
+		copied.Gamma = copyOrNil(layer.Gamma)
+		copied.Beta = copyOrNil(layer.Beta)
+		copied.RunningMean = copyOrNil(layer.RunningMean)
+		copied.RunningVariance = copyOrNil(layer.RunningVariance)
+		layers[i] = &copied
+	}
+	return layers
+}
+
+// copyOrNil returns a copy of m, or nil if m is nil.
+func copyOrNil(m *matrix.Matrix) *matrix.Matrix {
+	if m == nil {
+		return nil
+	}
+	return m.Copy()
+}
+
+// MarshalJSON stores the activation and the normalization by their registered
+// names next to the weights.
+func (l *Layer) MarshalJSON() ([]byte, error) {
+	type plain Layer
+	activation, err := activations.encode(l.activation().Name(), l.activation())
+	if err != nil {
+		return nil, err
+	}
+	var normalization json.RawMessage = []byte("null")
+	if l.Normalization != nil {
+		normalization, err = normalizations.encode(l.Normalization.Name(), l.Normalization)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(struct {
+		*plain
+		Activation    json.RawMessage
+		Normalization json.RawMessage
+	}{(*plain)(l), activation, normalization})
+}
+
+// UnmarshalJSON restores a layer written by MarshalJSON.
+func (l *Layer) UnmarshalJSON(data []byte) error {
+	type plain Layer
+	aux := struct {
+		*plain
+		Activation    json.RawMessage
+		Normalization json.RawMessage
+	}{plain: (*plain)(l)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	activation, err := activations.decode(aux.Activation)
+	if err != nil {
+		return err
+	}
+	normalization, err := normalizations.decode(aux.Normalization)
+	if err != nil {
+		return err
+	}
+	l.Activation = activation
+	l.Normalization = normalization
+	return nil
 }
 
 func (n *Neural) ExportJSON() ([]byte, error) {
@@ -28,7 +300,7 @@ func ImportJSON(data []byte) (*Neural, error) {
 	m := &Neural{}
 	err := json.Unmarshal(data, &m)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	return m, err
 }
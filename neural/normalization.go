@@ -0,0 +1,228 @@
+package neural
+
+import (
+	"math"
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Normalization normalizes the weighted sums of a layer before its activation.
+// The normalized values are scaled by the layer's Gamma and shifted by its Beta,
+// which are learned like the weights. BatchNorm and LayerNorm implement it.
+type Normalization interface {
+	Name() string
+	// normalize returns the normalized values of z for the given layer together
+	// with the inverse standard deviations that were used.
+	normalize(layer *Layer, z *matrix.Matrix, training bool) *normalized
+	// backward returns the gradient with respect to z given the gradient with
+	// respect to the normalized values.
+	backward(n *normalized, grad *matrix.Matrix) *matrix.Matrix
+}
+
+var normalizations = newRegistry[Normalization](errors.ErrUnknownNormalization)
+
+func init() {
+	for _, normalization := range []Normalization{BatchNorm{}, LayerNorm{}} {
+		normalizations.register(normalization.Name(), normalization)
+	}
+}
+
+// normalized holds the values backpropagation needs from a normalization.
+type normalized struct {
+	values *matrix.Matrix
+	invStd []float64
+	// batch holds the statistics BatchNorm used in training, nil otherwise.
+	batch *batchStatistics
+}
+
+// batchStatistics holds the mean and the variance of every node of a BatchNorm
+// layer over the samples of a batch.
+type batchStatistics struct {
+	mean, variance []float64
+}
+
+// BatchNorm normalizes every node over the samples of a batch. During training
+// it uses the statistics of the batch, which the update of the weights also moves
+// the layer's running mean and variance towards; FeedForword and Evaluate use the
+// running statistics instead. A single
+// sample normalizes to zero, so training with it needs a BatchSize larger than 1,
+// and a last batch of one sample is merged into the batch before it.
+type BatchNorm struct {
+	// Momentum is the weight of the previous running statistics in every update.
+	// Defaults to 0.9.
+	Momentum float64
+	// Epsilon is added to the variance for numerical stability. Defaults to 1e-5.
+	Epsilon float64
+}
+
+func (BatchNorm) Name() string { return "batch_norm" }
+
+func (b BatchNorm) normalize(layer *Layer, z *matrix.Matrix, training bool) *normalized {
+	epsilon := orDefault(b.Epsilon, 1e-5)
+
+	n := &normalized{values: matrix.New(z.Row, z.Col), invStd: make([]float64, z.Row)}
+	if training {
+		n.batch = &batchStatistics{mean: make([]float64, z.Row), variance: make([]float64, z.Row)}
+	}
+	for i, row := range z.Matrix {
+		mean, variance := layer.RunningMean.Matrix[i][0], layer.RunningVariance.Matrix[i][0]
+		if training {
+			mean, variance = moments(row)
+			n.batch.mean[i], n.batch.variance[i] = mean, variance
+		}
+
+		n.invStd[i] = 1 / math.Sqrt(variance+epsilon)
+		for j, value := range row {
+			n.values.Matrix[i][j] = (value - mean) * n.invStd[i]
+		}
+	}
+	return n
+}
+
+// track moves the running statistics of the layer towards the statistics of a
+// batch. Statistics of another shape, from the gradients of another network, are
+// ignored.
+func (b BatchNorm) track(layer *Layer, batch *batchStatistics) {
+	if len(batch.mean) != layer.RunningMean.Row {
+		return
+	}
+	momentum := orDefault(b.Momentum, 0.9)
+	for i := range batch.mean {
+		layer.RunningMean.Matrix[i][0] = momentum*layer.RunningMean.Matrix[i][0] + (1-momentum)*batch.mean[i]
+		layer.RunningVariance.Matrix[i][0] = momentum*layer.RunningVariance.Matrix[i][0] + (1-momentum)*batch.variance[i]
+	}
+}
+
+func (BatchNorm) backward(n *normalized, grad *matrix.Matrix) *matrix.Matrix {
+	result := matrix.New(grad.Row, grad.Col)
+	count := float64(grad.Col)
+	for i, row := range grad.Matrix {
+		sum, dot := 0.0, 0.0
+		for j, g := range row {
+			sum += g
+			dot += g * n.values.Matrix[i][j]
+		}
+		for j, g := range row {
+			result.Matrix[i][j] = n.invStd[i] * (g - sum/count - n.values.Matrix[i][j]*dot/count)
+		}
+	}
+	return result
+}
+
+// LayerNorm normalizes every sample over the nodes of the layer. It behaves the
+// same during training and inference and works with any batch size.
+type LayerNorm struct {
+	// Epsilon is added to the variance for numerical stability. Defaults to 1e-5.
+	Epsilon float64
+}
+
+func (LayerNorm) Name() string { return "layer_norm" }
+
+func (l LayerNorm) normalize(layer *Layer, z *matrix.Matrix, training bool) *normalized {
+	epsilon := orDefault(l.Epsilon, 1e-5)
+
+	n := &normalized{values: matrix.New(z.Row, z.Col), invStd: make([]float64, z.Col)}
+	for j := 0; j < z.Col; j++ {
+		column := z.Column(j)
+		mean, variance := moments(column)
+		n.invStd[j] = 1 / math.Sqrt(variance+epsilon)
+		for i, value := range column {
+			n.values.Matrix[i][j] = (value - mean) * n.invStd[j]
+		}
+	}
+	return n
+}
+
+func (LayerNorm) backward(n *normalized, grad *matrix.Matrix) *matrix.Matrix {
+	result := matrix.New(grad.Row, grad.Col)
+	count := float64(grad.Row)
+	for j := 0; j < grad.Col; j++ {
+		sum, dot := 0.0, 0.0
+		for i := 0; i < grad.Row; i++ {
+			sum += grad.Matrix[i][j]
+			dot += grad.Matrix[i][j] * n.values.Matrix[i][j]
+		}
+		for i := 0; i < grad.Row; i++ {
+			result.Matrix[i][j] = n.invStd[j] * (grad.Matrix[i][j] - sum/count - n.values.Matrix[i][j]*dot/count)
+		}
+	}
+	return result
+}
+
+// moments returns the mean and the biased variance of values.
+func moments(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return mean, variance / float64(len(values))
+}
+
+// normalize applies the layer's normalization to z, followed by Gamma and Beta.
+// Layers without a normalization return z unchanged and a nil cache.
+func (l *Layer) normalize(z *matrix.Matrix, training bool) (*matrix.Matrix, *normalized) {
+	if l.Normalization == nil {
+		return z, nil
+	}
+
+	n := l.Normalization.normalize(l, z, training)
+	result := matrix.New(z.Row, z.Col)
+	for i, row := range n.values.Matrix {
+		for j, value := range row {
+			result.Matrix[i][j] = l.Gamma.Matrix[i][0]*value + l.Beta.Matrix[i][0]
+		}
+	}
+	return result, n
+}
+
+// denormalize backpropagates grad, the gradient with respect to the output of the
+// layer's normalization, through Gamma, Beta and the normalization.
+//
+// Returns:
+//   - *matrix.Matrix: The gradient with respect to the weighted sums of the layer.
+//   - *matrix.Matrix: The gradient with respect to Gamma.
+//   - *matrix.Matrix: The gradient with respect to Beta.
+func (l *Layer) denormalize(n *normalized, grad *matrix.Matrix) (*matrix.Matrix, *matrix.Matrix, *matrix.Matrix) {
+	gamma := matrix.New(grad.Row, 1)
+	beta := grad.SumColumns()
+	scaled := matrix.New(grad.Row, grad.Col)
+	for i, row := range grad.Matrix {
+		for j, g := range row {
+			gamma.Matrix[i][0] += g * n.values.Matrix[i][j]
+			scaled.Matrix[i][j] = g * l.Gamma.Matrix[i][0]
+		}
+	}
+	return l.Normalization.backward(n, scaled), gamma, beta
+}
+
+// setNormalization attaches normalization to the layer and resets Gamma to ones,
+// Beta to zeros and, for BatchNorm, the running statistics to zero mean and unit
+// variance. A nil normalization removes them all.
+func (l *Layer) setNormalization(normalization Normalization) {
+	l.Normalization = normalization
+	l.Gamma, l.Beta, l.RunningMean, l.RunningVariance = nil, nil, nil, nil
+	if normalization == nil {
+		return
+	}
+
+	nodes := l.Weights.Row
+	l.Gamma, l.Beta = ones(nodes), matrix.New(nodes, 1)
+	if _, ok := normalization.(BatchNorm); ok {
+		l.RunningMean, l.RunningVariance = matrix.New(nodes, 1), ones(nodes)
+	}
+}
+
+// ones returns a column vector of the given size filled with ones.
+func ones(size int) *matrix.Matrix {
+	m := matrix.New(size, 1)
+	for i := range m.Matrix {
+		m.Matrix[i][0] = 1
+	}
+	return m
+}
@@ -0,0 +1,273 @@
+package neural
+
+import (
+	"math"
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Parameter is a trainable matrix of the network together with the gradient of the
+// loss with respect to it.
+type Parameter struct {
+	// Name identifies the parameter across steps, e.g. "layers.0.weights".
+	Name string
+	// Value is updated in place by the optimizer.
+	Value    *matrix.Matrix
+	Gradient *matrix.Matrix
+}
+
+// Optimizer updates the parameters of the network from their gradients. Optimizers
+// that keep per-parameter state key it on Parameter.Name and export it with the
+// model, so a resumed training run continues where the previous one stopped.
+type Optimizer interface {
+	// Name identifies the optimizer in exported models.
+	Name() string
+	// Step moves every parameter against its gradient using the given learning rate.
+	Step(parameters []Parameter, learningRate float64) error
+}
+
+var optimizers = newRegistry[Optimizer](errors.ErrUnknownOptimizer)
+
+func init() {
+	for _, optimizer := range []Optimizer{
+		&SGD{}, &RMSProp{}, &Adagrad{}, &Adam{}, &AdamW{},
+	} {
+		optimizers.register(optimizer.Name(), optimizer)
+	}
+}
+
+// RegisterOptimizer makes a custom optimizer available to ImportJSON. Models using
+// an optimizer that was not registered cannot be imported. The registered value
+// must be a pointer if the optimizer keeps state.
+func RegisterOptimizer(optimizer Optimizer) {
+	optimizers.register(optimizer.Name(), optimizer)
+}
+
+// state returns the matrix stored under name, creating a zero matrix with the
+// shape of like when there is none yet.
+func state(states map[string]*matrix.Matrix, name string, like *matrix.Matrix) *matrix.Matrix {
+	s, ok := states[name]
+	if !ok {
+		s = matrix.New(like.Row, like.Col)
+		states[name] = s
+	}
+	return s
+}
+
+// checkShape verifies that a parameter and its gradient have the same shape.
+func checkShape(parameter Parameter) error {
+	if parameter.Value.Row != parameter.Gradient.Row || parameter.Value.Col != parameter.Gradient.Col {
+		return errors.ErrMatricesDimensionsMustMatch
+	}
+	return nil
+}
+
+// rows returns the rows of the parameter's value and gradient for an update in
+// place. Both may be views of any layout: a value without contiguous rows is
+// updated through a dense copy, which store writes back.
+func rows(parameter Parameter) (value, gradient [][]float64, store func()) {
+	dense := parameter.Value.Contiguous()
+	store = func() {
+		if dense != parameter.Value {
+			dense.CopyInto(parameter.Value)
+		}
+	}
+	return dense.Matrix, parameter.Gradient.Contiguous().Matrix, store
+}
+
+// SGD is stochastic gradient descent. With a non-zero Momentum every step follows
+// a velocity that accumulates past gradients, and Nesterov evaluates the update
+// at the look-ahead position. The zero value is plain gradient descent.
+type SGD struct {
+	Momentum float64
+	Nesterov bool
+	Velocity map[string]*matrix.Matrix
+}
+
+func (*SGD) Name() string { return "sgd" }
+
+func (o *SGD) Step(parameters []Parameter, learningRate float64) error {
+	if o.Velocity == nil {
+		o.Velocity = map[string]*matrix.Matrix{}
+	}
+
+	for _, parameter := range parameters {
+		if err := checkShape(parameter); err != nil {
+			return err
+		}
+
+		value, gradient, store := rows(parameter)
+		if o.Momentum == 0 {
+			for i := range value {
+				for j := range value[i] {
+					value[i][j] -= learningRate * gradient[i][j]
+				}
+			}
+			store()
+			continue
+		}
+
+		velocity := state(o.Velocity, parameter.Name, parameter.Value).Matrix
+		for i := range value {
+			for j := range value[i] {
+				velocity[i][j] = o.Momentum*velocity[i][j] + gradient[i][j]
+				update := velocity[i][j]
+				if o.Nesterov {
+					update = gradient[i][j] + o.Momentum*velocity[i][j]
+				}
+				value[i][j] -= learningRate * update
+			}
+		}
+		store()
+	}
+	return nil
+}
+
+// RMSProp divides every step by a moving average of the squared gradients.
+// Rho defaults to 0.9 and Epsilon to 1e-8.
+type RMSProp struct {
+	Rho     float64
+	Epsilon float64
+	Cache   map[string]*matrix.Matrix
+}
+
+func (*RMSProp) Name() string { return "rmsprop" }
+
+func (o *RMSProp) Step(parameters []Parameter, learningRate float64) error {
+	if o.Cache == nil {
+		o.Cache = map[string]*matrix.Matrix{}
+	}
+	rho := orDefault(o.Rho, 0.9)
+	eps := orDefault(o.Epsilon, 1e-8)
+
+	for _, parameter := range parameters {
+		if err := checkShape(parameter); err != nil {
+			return err
+		}
+
+		value, gradient, store := rows(parameter)
+		cache := state(o.Cache, parameter.Name, parameter.Value).Matrix
+		for i := range value {
+			for j := range value[i] {
+				g := gradient[i][j]
+				cache[i][j] = rho*cache[i][j] + (1-rho)*g*g
+				value[i][j] -= learningRate * g / (math.Sqrt(cache[i][j]) + eps)
+			}
+		}
+		store()
+	}
+	return nil
+}
+
+// Adagrad divides every step by the root of all squared gradients seen so far,
+// so frequently updated parameters slow down. Epsilon defaults to 1e-8.
+type Adagrad struct {
+	Epsilon float64
+	Cache   map[string]*matrix.Matrix
+}
+
+func (*Adagrad) Name() string { return "adagrad" }
+
+func (o *Adagrad) Step(parameters []Parameter, learningRate float64) error {
+	if o.Cache == nil {
+		o.Cache = map[string]*matrix.Matrix{}
+	}
+	eps := orDefault(o.Epsilon, 1e-8)
+
+	for _, parameter := range parameters {
+		if err := checkShape(parameter); err != nil {
+			return err
+		}
+
+		value, gradient, store := rows(parameter)
+		cache := state(o.Cache, parameter.Name, parameter.Value).Matrix
+		for i := range value {
+			for j := range value[i] {
+				g := gradient[i][j]
+				cache[i][j] += g * g
+				value[i][j] -= learningRate * g / (math.Sqrt(cache[i][j]) + eps)
+			}
+		}
+		store()
+	}
+	return nil
+}
+
+// Adam keeps bias-corrected moving averages of the gradients (M) and squared
+// gradients (V). Beta1 defaults to 0.9, Beta2 to 0.999 and Epsilon to 1e-8.
+// Steps counts the updates performed so far.
+type Adam struct {
+	Beta1   float64
+	Beta2   float64
+	Epsilon float64
+	Steps   int
+	M       map[string]*matrix.Matrix
+	V       map[string]*matrix.Matrix
+}
+
+func (*Adam) Name() string { return "adam" }
+
+func (o *Adam) Step(parameters []Parameter, learningRate float64) error {
+	return o.step(parameters, learningRate, 0)
+}
+
+// step performs an Adam update. A non-zero weightDecay shrinks every parameter
+// towards zero independently of its gradient, as done by AdamW.
+func (o *Adam) step(parameters []Parameter, learningRate, weightDecay float64) error {
+	if o.M == nil {
+		o.M = map[string]*matrix.Matrix{}
+	}
+	if o.V == nil {
+		o.V = map[string]*matrix.Matrix{}
+	}
+	beta1 := orDefault(o.Beta1, 0.9)
+	beta2 := orDefault(o.Beta2, 0.999)
+	eps := orDefault(o.Epsilon, 1e-8)
+
+	o.Steps++
+	correction1 := 1 - math.Pow(beta1, float64(o.Steps))
+	correction2 := 1 - math.Pow(beta2, float64(o.Steps))
+
+	for _, parameter := range parameters {
+		if err := checkShape(parameter); err != nil {
+			return err
+		}
+
+		value, gradient, store := rows(parameter)
+		m := state(o.M, parameter.Name, parameter.Value).Matrix
+		v := state(o.V, parameter.Name, parameter.Value).Matrix
+		for i := range value {
+			for j := range value[i] {
+				g := gradient[i][j]
+				m[i][j] = beta1*m[i][j] + (1-beta1)*g
+				v[i][j] = beta2*v[i][j] + (1-beta2)*g*g
+				mHat := m[i][j] / correction1
+				vHat := v[i][j] / correction2
+				value[i][j] -= learningRate * (mHat/(math.Sqrt(vHat)+eps) + weightDecay*value[i][j])
+			}
+		}
+		store()
+	}
+	return nil
+}
+
+// AdamW is Adam with decoupled weight decay: every step also shrinks the
+// parameters by learningRate * WeightDecay, independently of the gradients.
+type AdamW struct {
+	Adam
+	WeightDecay float64
+}
+
+func (*AdamW) Name() string { return "adamw" }
+
+func (o *AdamW) Step(parameters []Parameter, learningRate float64) error {
+	return o.step(parameters, learningRate, o.WeightDecay)
+}
+
+// orDefault returns value, or fallback when value is zero.
+func orDefault(value, fallback float64) float64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
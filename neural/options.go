@@ -0,0 +1,208 @@
+package neural
+
+import (
+	"math/rand/v2"
+	"neuraln/errors"
+	"neuraln/matrix"
+)
+
+// Option configures a network created with CreateDeep.
+type Option func(neural *Neural) error
+
+// WithActivation sets the activation of a single layer. Layers are indexed from
+// the first hidden layer, so the output layer is len(sizes)-2.
+func WithActivation(layer int, activation Activation) Option {
+	return func(neural *Neural) error {
+		if layer < 0 || layer >= len(neural.Layers) {
+			return errors.ErrLayerOutOfRange
+		}
+		neural.Layers[layer].Activation = activation
+		return nil
+	}
+}
+
+// WithHiddenActivation sets the activation of every hidden layer.
+func WithHiddenActivation(activation Activation) Option {
+	return func(neural *Neural) error {
+		for _, layer := range neural.Layers[:len(neural.Layers)-1] {
+			layer.Activation = activation
+		}
+		return nil
+	}
+}
+
+// WithOutputActivation sets the activation of the output layer.
+func WithOutputActivation(activation Activation) Option {
+	return func(neural *Neural) error {
+		neural.Layers[len(neural.Layers)-1].Activation = activation
+		return nil
+	}
+}
+
+// WithInitializer sets the initializers of the weights and the biases of a single
+// layer. A nil initializer keeps the default, RandomUniform{Low: -1, High: 1}.
+func WithInitializer(layer int, weights, bias Initializer) Option {
+	return func(neural *Neural) error {
+		if layer < 0 || layer >= len(neural.Layers) {
+			return errors.ErrLayerOutOfRange
+		}
+		neural.Layers[layer].weightsInitializer = weights
+		neural.Layers[layer].biasInitializer = bias
+		return nil
+	}
+}
+
+// WithInitializers sets the initializers of the weights and the biases of every
+// layer, e.g. WithInitializers(XavierUniform{}, Zeros{}).
+func WithInitializers(weights, bias Initializer) Option {
+	return func(neural *Neural) error {
+		for _, layer := range neural.Layers {
+			layer.weightsInitializer = weights
+			layer.biasInitializer = bias
+		}
+		return nil
+	}
+}
+
+// WithRegularization sets the L1 and L2 penalty coefficients of a single layer.
+func WithRegularization(layer int, l1, l2 float64) Option {
+	return func(neural *Neural) error {
+		if layer < 0 || layer >= len(neural.Layers) {
+			return errors.ErrLayerOutOfRange
+		}
+		neural.Layers[layer].L1 = l1
+		neural.Layers[layer].L2 = l2
+		return nil
+	}
+}
+
+// WithGlobalRegularization sets the L1 and L2 penalty coefficients of every layer.
+func WithGlobalRegularization(l1, l2 float64) Option {
+	return func(neural *Neural) error {
+		for _, layer := range neural.Layers {
+			layer.L1 = l1
+			layer.L2 = l2
+		}
+		return nil
+	}
+}
+
+// WithNormalization normalizes the weighted sums of a single layer with
+// normalization, e.g. BatchNorm{} or LayerNorm{}. A nil normalization removes it.
+func WithNormalization(layer int, normalization Normalization) Option {
+	return func(neural *Neural) error {
+		if layer < 0 || layer >= len(neural.Layers) {
+			return errors.ErrLayerOutOfRange
+		}
+		neural.Layers[layer].setNormalization(normalization)
+		return nil
+	}
+}
+
+// WithHiddenNormalization normalizes the weighted sums of every hidden layer.
+func WithHiddenNormalization(normalization Normalization) Option {
+	return func(neural *Neural) error {
+		for _, layer := range neural.Layers[:len(neural.Layers)-1] {
+			layer.setNormalization(normalization)
+		}
+		return nil
+	}
+}
+
+// WithDropout sets the dropout rate of a single hidden layer. The output layer
+// cannot use dropout.
+func WithDropout(layer int, rate float64) Option {
+	return func(neural *Neural) error {
+		if layer < 0 || layer >= len(neural.Layers)-1 {
+			return errors.ErrLayerOutOfRange
+		}
+		if rate < 0 || rate >= 1 {
+			return errors.ErrDropoutRate
+		}
+		neural.Layers[layer].Dropout = rate
+		return nil
+	}
+}
+
+// WithHiddenDropout sets the dropout rate of every hidden layer.
+func WithHiddenDropout(rate float64) Option {
+	return func(neural *Neural) error {
+		if rate < 0 || rate >= 1 {
+			return errors.ErrDropoutRate
+		}
+		for _, layer := range neural.Layers[:len(neural.Layers)-1] {
+			layer.Dropout = rate
+		}
+		return nil
+	}
+}
+
+// WithWeightDecay sets the decoupled weight decay applied before every optimizer step.
+func WithWeightDecay(weightDecay float64) Option {
+	return func(neural *Neural) error {
+		neural.WeightDecay = weightDecay
+		return nil
+	}
+}
+
+// WithLoss sets the loss minimized by Train.
+func WithLoss(loss Loss) Option {
+	return func(neural *Neural) error {
+		neural.Loss = loss
+		return nil
+	}
+}
+
+// WithOptimizer sets the optimizer that applies the gradients during Train.
+func WithOptimizer(optimizer Optimizer) Option {
+	return func(neural *Neural) error {
+		neural.Optimizer = optimizer
+		return nil
+	}
+}
+
+// WithLearningRate sets the learning rate passed to the optimizer.
+func WithLearningRate(learningRate float64) Option {
+	return func(neural *Neural) error {
+		neural.LearningRate = learningRate
+		return nil
+	}
+}
+
+// WithBackend makes the network run its backend operations, the matrix
+// multiplications, the Sigmoid activation and the dropout masks, on the backend
+// registered under name, see matrix.Backends, instead of the process-wide
+// matrix.DefaultBackend. The backend is not saved by ExportJSON.
+func WithBackend(name string) Option {
+	return func(neural *Neural) error {
+		backend, err := matrix.GetBackend(name)
+		if err != nil {
+			return err
+		}
+		neural.backend = backend
+		return nil
+	}
+}
+
+// WithSeed seeds the network's random number generator, which drives weight
+// initialization, shuffling and dropout, and the one of the dropout masks of
+// Gradients. Networks created with the same seed and trained on the same data end
+// up with bit-identical weights on the CPU.
+func WithSeed(seed uint64) Option {
+	return func(neural *Neural) error {
+		neural.rng = rand.New(rand.NewPCG(seed, seed))
+		neural.dropoutRng = rand.New(rand.NewPCG(seed, ^seed))
+		return nil
+	}
+}
+
+// WithSource makes the network draw its random numbers from source. The dropout
+// masks of Gradients come from a generator seeded with the first two numbers of
+// source, so the same source again reproduces them as well.
+func WithSource(source rand.Source) Option {
+	return func(neural *Neural) error {
+		neural.dropoutRng = rand.New(rand.NewPCG(source.Uint64(), source.Uint64()))
+		neural.rng = rand.New(source)
+		return nil
+	}
+}
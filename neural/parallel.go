@@ -0,0 +1,105 @@
+package neural
+
+import (
+	"math/rand/v2"
+	"neuraln/matrix"
+	"sync"
+)
+
+// shardedGradients computes the gradients of a batch like computeGradients, but
+// splits the samples into up to workers shards whose gradients are computed on
+// goroutines of their own. Every goroutine only reads the parameters of the
+// network. The dropout masks are drawn for the whole batch before it is split, so
+// the gradients match those of a single goroutine for the same random numbers,
+// within floating-point tolerance. Networks with BatchNorm layers use a single
+// goroutine, since their batch statistics need every sample of the batch.
+//
+// Returns:
+//   - *Gradients: The gradients and the loss averaged over the whole batch.
+//   - *matrix.Matrix: The outputs of the network for every sample of the batch.
+//   - error: An error if any matrix operation fails, otherwise nil.
+func (neural *Neural) shardedGradients(inputArray, targetArray [][]float64, r *rand.Rand, workers int, ws *workspace) (*Gradients, *matrix.Matrix, error) {
+	samples := len(inputArray)
+	masks := neural.dropoutMasks(r, samples, ws)
+
+	shards := workers
+	if shards > samples {
+		shards = samples
+	}
+	if shards <= 1 || neural.batchNormalized() {
+		return neural.backPropagate(ws.columns(inputArray, false), ws.columns(targetArray, true), masks, ws)
+	}
+
+	type result struct {
+		grads   *Gradients
+		outputs *matrix.Matrix
+		err     error
+	}
+	results := make([]result, shards)
+
+	var wg sync.WaitGroup
+	for k := 0; k < shards; k++ {
+		from, to := k*samples/shards, (k+1)*samples/shards
+		shardSpace := ws.shard(k)
+		wg.Add(1)
+		go func(k, from, to int) {
+			defer wg.Done()
+			shardMasks := make([]*matrix.Matrix, len(masks))
+			for i, mask := range masks {
+				if mask != nil {
+					shardMasks[i] = mask.View(0, from, mask.Row, to-from)
+				}
+			}
+			grads, outputs, err := neural.backPropagate(shardSpace.columns(inputArray[from:to], false), shardSpace.columns(targetArray[from:to], true), shardMasks, shardSpace)
+			if err == nil {
+				// Weight every shard by its share of the samples
+				grads.Scale(float64(to-from) / float64(samples))
+			}
+			results[k] = result{grads, outputs, err}
+		}(k, from, to)
+	}
+	wg.Wait()
+
+	// Reduce in shard order so the result does not depend on scheduling
+	outputs := make([]*matrix.Matrix, shards)
+	for k, result := range results {
+		if result.err != nil {
+			return nil, nil, result.err
+		}
+		outputs[k] = result.outputs
+		if k > 0 {
+			if err := results[0].grads.Add(result.grads); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return results[0].grads, joinColumns(outputs), nil
+}
+
+// batchNormalized reports whether any layer of the network uses BatchNorm.
+func (neural *Neural) batchNormalized() bool {
+	for _, layer := range neural.Layers {
+		if _, ok := layer.Normalization.(BatchNorm); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// joinColumns places the columns of every part side by side in a single matrix.
+func joinColumns(parts []*matrix.Matrix) *matrix.Matrix {
+	cols := 0
+	for _, part := range parts {
+		cols += part.Col
+	}
+
+	result := matrix.New(parts[0].Row, cols)
+	offset := 0
+	for _, part := range parts {
+		for i, row := range part.Matrix {
+			copy(result.Matrix[i][offset:], row)
+		}
+		offset += part.Col
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package neural
+
+import (
+	"neuraln/errors"
+	"neuraln/matrix"
+	"runtime"
+	"sync"
+)
+
+// predictBatchSize is the number of samples PredictBatch packs into one matrix.
+const predictBatchSize = 1024
+
+// PredictBatch computes the outputs of the network for many samples at once. The
+// samples are packed as the columns of matrices of up to predictBatchSize samples,
+// so every layer performs one matrix product per chunk instead of one per sample.
+// Inputs larger than a single chunk are spread over a pool of up to GOMAXPROCS
+// goroutines. Like FeedForword, PredictBatch runs the network for inference and
+// does not change it.
+//
+// Parameters:
+//   - inputArray: The input samples, one per row.
+//
+// Returns:
+//   - [][]float64: The outputs of every sample, in the order of the inputs.
+//   - error: A *errors.RowError wrapping errors.ErrInputNodesMismatch for the first
+//     row whose length does not match the number of input nodes, otherwise nil.
+func (neural *Neural) PredictBatch(inputArray [][]float64) ([][]float64, error) {
+	for i, input := range inputArray {
+		if len(input) != neural.InputNodes {
+			return nil, &errors.RowError{Row: i, Err: errors.ErrInputNodesMismatch}
+		}
+	}
+
+	outputs := make([][]float64, len(inputArray))
+	chunks := (len(inputArray) + predictBatchSize - 1) / predictBatchSize
+	workers := runtime.GOMAXPROCS(0)
+	if workers > chunks {
+		workers = chunks
+	}
+	if workers <= 1 {
+		for chunk := 0; chunk < chunks; chunk++ {
+			if err := neural.predictChunk(inputArray, outputs, chunk); err != nil {
+				return nil, err
+			}
+		}
+		return outputs, nil
+	}
+
+	jobs := make(chan int)
+	errs := make([]error, chunks)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				errs[chunk] = neural.predictChunk(inputArray, outputs, chunk)
+			}
+		}()
+	}
+	for chunk := 0; chunk < chunks; chunk++ {
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// predictChunk runs one chunk of predictBatchSize samples through the network and
+// stores their outputs at the same indices of outputs.
+func (neural *Neural) predictChunk(inputArray, outputs [][]float64, chunk int) error {
+	start := chunk * predictBatchSize
+	end := start + predictBatchSize
+	if end > len(inputArray) {
+		end = len(inputArray)
+	}
+
+	pass, err := neural.forward(matrix.NewFromColumns(inputArray[start:end]), false, nil, nil)
+	if err != nil {
+		return err
+	}
+	for j := 0; j < end-start; j++ {
+		outputs[start+j] = pass.outputs().Column(j)
+	}
+	return nil
+}
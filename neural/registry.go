@@ -0,0 +1,81 @@
+package neural
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// named is the JSON envelope used to persist pluggable components: the name the
+// component was registered under and its own exported fields.
+type named struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// registry maps component names to prototype values so that components can be
+// restored from their JSON envelope.
+type registry[T any] struct {
+	prototypes map[string]T
+	unknown    error
+}
+
+func newRegistry[T any](unknown error) *registry[T] {
+	return &registry[T]{prototypes: map[string]T{}, unknown: unknown}
+}
+
+// register makes prototype available to decode under the given name.
+func (r *registry[T]) register(name string, prototype T) {
+	r.prototypes[name] = prototype
+}
+
+// encode wraps the component in its named envelope. A nil component is encoded
+// as JSON null.
+func (r *registry[T]) encode(name string, component T) (json.RawMessage, error) {
+	if any(component) == nil {
+		return json.RawMessage("null"), nil
+	}
+
+	config, err := json.Marshal(component)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(named{Name: name, Config: config})
+}
+
+// decode restores a component from its named envelope. The returned value has the
+// same concrete type as the registered prototype, whether that is a struct or a
+// pointer to one.
+func (r *registry[T]) decode(data []byte) (T, error) {
+	var zero T
+	if len(data) == 0 || string(data) == "null" {
+		return zero, nil
+	}
+
+	var envelope named
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return zero, err
+	}
+
+	prototype, ok := r.prototypes[envelope.Name]
+	if !ok {
+		return zero, r.unknown
+	}
+
+	typ := reflect.TypeOf(prototype)
+	pointer := typ.Kind() == reflect.Pointer
+	if pointer {
+		typ = typ.Elem()
+	}
+
+	value := reflect.New(typ)
+	if len(envelope.Config) > 0 {
+		if err := json.Unmarshal(envelope.Config, value.Interface()); err != nil {
+			return zero, err
+		}
+	}
+
+	if pointer {
+		return value.Interface().(T), nil
+	}
+	return value.Elem().Interface().(T), nil
+}
@@ -0,0 +1,74 @@
+package neural
+
+import (
+	"math"
+	"neuraln/matrix"
+)
+
+// penalty returns the L1 and L2 penalties of every layer, which are added to the
+// loss of the network.
+func (neural *Neural) penalty() float64 {
+	total := 0.0
+	for _, layer := range neural.Layers {
+		if layer.L1 == 0 && layer.L2 == 0 {
+			continue
+		}
+		total += layer.penalty(layer.Weights)
+		if layer.RegularizeBias {
+			total += layer.penalty(layer.Bias)
+		}
+	}
+	return total
+}
+
+// penalty returns L1 * sum(|w|) + L2 * sum(w^2) over the values of m.
+func (l *Layer) penalty(m *matrix.Matrix) float64 {
+	total := 0.0
+	for _, row := range m.Contiguous().Matrix {
+		for _, w := range row {
+			total += l.L1*math.Abs(w) + l.L2*w*w
+		}
+	}
+	return total
+}
+
+// regularize adds the gradient of the layer's penalty on m to grad, a dense
+// matrix computed by backpropagation, in place.
+func (l *Layer) regularize(m, grad *matrix.Matrix) {
+	if l.L1 == 0 && l.L2 == 0 {
+		return
+	}
+	for i, row := range m.Contiguous().Matrix {
+		for j, w := range row {
+			sign := 0.0
+			switch {
+			case w > 0:
+				sign = 1
+			case w < 0:
+				sign = -1
+			}
+			grad.Matrix[i][j] += l.L1*sign + 2*l.L2*w
+		}
+	}
+}
+
+// decay shrinks the weights of every layer, and the biases of layers with
+// RegularizeBias, by learningRate * WeightDecay, independently of the gradients.
+func (neural *Neural) decay(learningRate float64) {
+	if neural.WeightDecay == 0 {
+		return
+	}
+
+	factor := 1 - learningRate*neural.WeightDecay
+	for _, layer := range neural.Layers {
+		scale(layer.Weights, factor)
+		if layer.RegularizeBias {
+			scale(layer.Bias, factor)
+		}
+	}
+}
+
+// scale multiplies every value of m by factor, in place.
+func scale(m *matrix.Matrix, factor float64) {
+	m.ScalerMulInPlace(factor)
+}
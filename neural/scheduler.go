@@ -0,0 +1,156 @@
+package neural
+
+import "math"
+
+// Scheduler decides the learning rate of every training step. Train consults it
+// before each step with the network's LearningRate as base rate, the zero-based
+// epoch and the zero-based number of steps taken since training started.
+type Scheduler interface {
+	LearningRate(base float64, epoch, step int) float64
+}
+
+// PlateauScheduler is a Scheduler that reacts to the monitored loss. Train calls
+// Observe at the end of every epoch with the validation loss when validation data
+// is available and with the training loss otherwise.
+type PlateauScheduler interface {
+	Scheduler
+	Observe(loss float64)
+}
+
+// StepDecay multiplies the rate by Gamma every StepSize epochs.
+type StepDecay struct {
+	StepSize int
+	Gamma    float64
+}
+
+func (s StepDecay) LearningRate(base float64, epoch, step int) float64 {
+	if s.StepSize < 1 {
+		return base
+	}
+	return base * math.Pow(s.Gamma, float64(epoch/s.StepSize))
+}
+
+// ExponentialDecay multiplies the rate by Gamma every epoch.
+type ExponentialDecay struct {
+	Gamma float64
+}
+
+func (s ExponentialDecay) LearningRate(base float64, epoch, step int) float64 {
+	return base * math.Pow(s.Gamma, float64(epoch))
+}
+
+// CosineAnnealing lowers the rate from base to MinRate along a cosine over Period
+// epochs and then restarts. Every restart multiplies the period by Mult, which
+// defaults to 1.
+type CosineAnnealing struct {
+	Period  int
+	Mult    int
+	MinRate float64
+}
+
+func (s CosineAnnealing) LearningRate(base float64, epoch, step int) float64 {
+	if s.Period < 1 {
+		return base
+	}
+	mult := s.Mult
+	if mult < 1 {
+		mult = 1
+	}
+
+	// Find the position of epoch inside its restart period
+	period := s.Period
+	for epoch >= period {
+		epoch -= period
+		period *= mult
+	}
+
+	progress := float64(epoch) / float64(period)
+	return s.MinRate + (base-s.MinRate)*(1+math.Cos(math.Pi*progress))/2
+}
+
+// OneCycle raises the rate from MaxRate/DivFactor to MaxRate over the first
+// PctStart of TotalSteps and then anneals it to MaxRate/(DivFactor*FinalDivFactor),
+// both along a cosine. MaxRate defaults to the base rate, PctStart to 0.3,
+// DivFactor to 25 and FinalDivFactor to 1e4.
+type OneCycle struct {
+	MaxRate        float64
+	TotalSteps     int
+	PctStart       float64
+	DivFactor      float64
+	FinalDivFactor float64
+}
+
+func (s OneCycle) LearningRate(base float64, epoch, step int) float64 {
+	if s.TotalSteps < 1 {
+		return base
+	}
+	maxRate := orDefault(s.MaxRate, base)
+	initial := maxRate / orDefault(s.DivFactor, 25)
+	final := initial / orDefault(s.FinalDivFactor, 1e4)
+	warmup := orDefault(s.PctStart, 0.3) * float64(s.TotalSteps)
+
+	// anneal moves from start to end along a cosine as progress goes from 0 to 1
+	anneal := func(start, end, progress float64) float64 {
+		return end + (start-end)*(1+math.Cos(math.Pi*math.Min(progress, 1)))/2
+	}
+
+	if float64(step) < warmup {
+		return anneal(initial, maxRate, float64(step)/warmup)
+	}
+	// Without steps left to anneal over, e.g. for a PctStart of 1, the cycle is over
+	remaining := float64(s.TotalSteps) - warmup
+	if remaining <= 0 {
+		return final
+	}
+	return anneal(maxRate, final, (float64(step)-warmup)/remaining)
+}
+
+// ReduceOnPlateau multiplies the rate by Factor whenever the observed loss has not
+// improved by more than MinDelta for more than Patience epochs, but never goes below MinRate.
+// After a reduction it waits Cooldown epochs before counting again. Factor
+// defaults to 0.1. Use a pointer, the scheduler keeps state between epochs.
+type ReduceOnPlateau struct {
+	Factor   float64
+	Patience int
+	MinDelta float64
+	MinRate  float64
+	Cooldown int
+
+	scale    float64
+	best     float64
+	bad      int
+	cooldown int
+	seen     bool
+}
+
+func (s *ReduceOnPlateau) LearningRate(base float64, epoch, step int) float64 {
+	if s.scale == 0 {
+		s.scale = 1
+	}
+	return math.Max(base*s.scale, s.MinRate)
+}
+
+func (s *ReduceOnPlateau) Observe(loss float64) {
+	if s.scale == 0 {
+		s.scale = 1
+	}
+
+	if !s.seen || loss < s.best-s.MinDelta {
+		s.best = loss
+		s.bad = 0
+		s.seen = true
+		return
+	}
+
+	if s.cooldown > 0 {
+		s.cooldown--
+		return
+	}
+
+	s.bad++
+	if s.bad > s.Patience {
+		s.scale *= orDefault(s.Factor, 0.1)
+		s.bad = 0
+		s.cooldown = s.Cooldown
+	}
+}
@@ -0,0 +1,65 @@
+package neural_test
+
+import (
+	"math/rand/v2"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestGradientAccumulation(t *testing.T) {
+	inputs := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {0.5, 0}, {0, 0.5}, {0.5, 0.5}}
+	targets := [][]float64{{0}, {1}, {1}, {0}, {1}, {1}, {0}}
+
+	train := func(options neural.TrainOptions) *neural.Neural {
+		n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 1}, neural.WithSeed(8), neural.WithOptimizer(&neural.Adam{}), neural.WithLearningRate(0.01))
+		if err != nil {
+			t.Fatalf("CreateDeep failed: %v", err)
+		}
+		// Identical shuffles for both runs
+		options.Source = rand.NewPCG(11, 11)
+		if _, err := n.TrainWithOptions(inputs, targets, options); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+		return n
+	}
+
+	// 7 samples: one step of 7 samples against batches of 2, 2, 2 and 1 accumulated
+	// into a single step
+	large := train(neural.TrainOptions{Epochs: 5, BatchSize: 7})
+	accumulated := train(neural.TrainOptions{Epochs: 5, BatchSize: 2, AccumulationSteps: 4})
+	for i := range large.Layers {
+		assertClose(t, "weights", large.Layers[i].Weights, accumulated.Layers[i].Weights)
+		assertClose(t, "bias", large.Layers[i].Bias, accumulated.Layers[i].Bias)
+	}
+}
+
+func TestGradientAccumulationSteps(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 4, 1})
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+
+	var batches []neural.BatchLog
+	options := neural.TrainOptions{
+		Epochs:            2,
+		BatchSize:         1,
+		AccumulationSteps: 3,
+		Callbacks: []neural.Callback{{OnBatchEnd: func(log neural.BatchLog) error {
+			batches = append(batches, log)
+			return nil
+		}}},
+	}
+	if _, err := n.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	// 4 samples make a step of 3 and a step of 1 per epoch
+	if len(batches) != 4 {
+		t.Fatalf("Expected 4 steps, got %d", len(batches))
+	}
+	for i, log := range batches {
+		if log.Step != i || log.Batch != i%2 {
+			t.Errorf("Expected step %d to be batch %d, got step %d batch %d", i, i%2, log.Step, log.Batch)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestActivationGradients(t *testing.T) {
+	z := matrix.New(3, 2)
+	z.Matrix = [][]float64{
+		{-1.5, 0.3},
+		{0.7, -0.2},
+		{2.1, 1.2},
+	}
+
+	activations := []neural.Activation{
+		neural.Sigmoid{}, neural.Tanh{}, neural.ReLU{}, neural.LeakyReLU{Alpha: 0.1},
+		neural.ELU{Alpha: 1}, neural.GELU{}, neural.Linear{}, neural.Softmax{},
+	}
+
+	// Use the sum of the weighted outputs as loss so the upstream gradient is the weights
+	weights := matrix.New(3, 2)
+	weights.Matrix = [][]float64{
+		{0.5, -1},
+		{2, 0.25},
+		{-0.75, 1.5},
+	}
+	loss := func(a neural.Activation, z *matrix.Matrix) float64 {
+		out, _ := a.Forward(z).HadProduct(weights)
+		sum := 0.0
+		for _, v := range out.Flatten() {
+			sum += v
+		}
+		return sum
+	}
+
+	const h = 1e-6
+	for _, activation := range activations {
+		grad, err := activation.Backward(z, activation.Forward(z), weights)
+		if err != nil {
+			t.Fatalf("%s: Backward failed: %v", activation.Name(), err)
+		}
+
+		for i := 0; i < z.Row; i++ {
+			for j := 0; j < z.Col; j++ {
+				plus := matrix.New(z.Row, z.Col)
+				minus := matrix.New(z.Row, z.Col)
+				for r := range z.Matrix {
+					copy(plus.Matrix[r], z.Matrix[r])
+					copy(minus.Matrix[r], z.Matrix[r])
+				}
+				plus.Matrix[i][j] += h
+				minus.Matrix[i][j] -= h
+
+				numeric := (loss(activation, plus) - loss(activation, minus)) / (2 * h)
+				if math.Abs(numeric-grad.Matrix[i][j]) > 1e-5 {
+					t.Errorf("%s at (%d, %d): expected gradient %v, got %v", activation.Name(), i, j, numeric, grad.Matrix[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestActivationDefaultAlpha(t *testing.T) {
+	z := matrix.New(1, 1)
+	z.Matrix = [][]float64{{-2}}
+
+	cases := []struct {
+		activation neural.Activation
+		expected   float64
+	}{
+		{neural.LeakyReLU{}, -0.02},
+		{neural.ELU{}, math.Expm1(-2)},
+	}
+	for _, c := range cases {
+		if actual := c.activation.Forward(z).Matrix[0][0]; math.Abs(actual-c.expected) > 1e-12 {
+			t.Errorf("%s: expected %v for a zero Alpha, got %v", c.activation.Name(), c.expected, actual)
+		}
+	}
+}
+
+func TestSoftmaxIsStable(t *testing.T) {
+	z := matrix.New(3, 1)
+	z.Matrix = [][]float64{{1000}, {1001}, {1002}}
+
+	result := neural.Softmax{}.Forward(z)
+
+	sum := 0.0
+	for _, v := range result.Flatten() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Fatalf("Expected finite probabilities, got %v", result.Matrix)
+		}
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("Expected probabilities to sum to 1, got %v", sum)
+	}
+	if math.Abs(result.Matrix[2][0]-0.6652409557748219) > 1e-12 {
+		t.Errorf("Expected 0.6652409557748219, got %v", result.Matrix[2][0])
+	}
+}
+
+func TestActivationExportImport(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 3, 3, 2},
+		neural.WithActivation(0, neural.LeakyReLU{Alpha: 0.2}),
+		neural.WithActivation(1, neural.Tanh{}),
+		neural.WithOutputActivation(neural.Softmax{}),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	data, err := nn.ExportJSON()
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+
+	imported, err := neuraln.ImportJSON(data)
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	expected := []neural.Activation{neural.LeakyReLU{Alpha: 0.2}, neural.Tanh{}, neural.Softmax{}}
+	for i, layer := range imported.Layers {
+		if layer.Activation != expected[i] {
+			t.Errorf("Layer %d: expected %#v, got %#v", i, expected[i], layer.Activation)
+		}
+	}
+
+	input := []float64{0.3, -0.8}
+	want, _ := nn.Predict(input)
+	got, err := imported.FeedForword(input)
+	if err != nil {
+		t.Fatalf("FeedForword failed: %v", err)
+	}
+	for i, v := range got.Flatten() {
+		if v != want[i] {
+			t.Errorf("Expected imported prediction %v, got %v", want, got.Flatten())
+			break
+		}
+	}
+}
+
+func TestWithActivationOutOfRange(t *testing.T) {
+	if _, err := neuraln.NewDeep([]int{2, 3, 1}, neural.WithActivation(2, neural.ReLU{})); err == nil {
+		t.Error("Expected an error for an out of range layer index")
+	}
+}
@@ -0,0 +1,117 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"sync/atomic"
+	"testing"
+)
+
+// countingBackend delegates to the cpu backend and counts the matrix products and
+// the element-wise operations of the network.
+type countingBackend struct {
+	matrix.Backend
+	name                          string
+	products, sigmoids, hadamards atomic.Int64
+}
+
+func (c *countingBackend) Name() string { return c.name }
+
+func (c *countingBackend) Mul(a, b *matrix.Matrix) (*matrix.Matrix, error) {
+	c.products.Add(1)
+	return c.Backend.Mul(a, b)
+}
+
+func (c *countingBackend) Gemm(transA, transB bool, alpha float64, a, b *matrix.Matrix, beta float64, result *matrix.Matrix) error {
+	c.products.Add(1)
+	return c.Backend.Gemm(transA, transB, alpha, a, b, beta, result)
+}
+
+func (c *countingBackend) Sigmoid(a *matrix.Matrix) *matrix.Matrix {
+	c.sigmoids.Add(1)
+	return c.Backend.Sigmoid(a)
+}
+
+func (c *countingBackend) DSigmoid(a *matrix.Matrix) *matrix.Matrix {
+	c.sigmoids.Add(1)
+	return c.Backend.DSigmoid(a)
+}
+
+func (c *countingBackend) Hadamard(a, b *matrix.Matrix) (*matrix.Matrix, error) {
+	c.hadamards.Add(1)
+	return c.Backend.Hadamard(a, b)
+}
+
+// newCountingBackend registers a countingBackend under name.
+func newCountingBackend(t *testing.T, name string) *countingBackend {
+	t.Helper()
+	cpu, err := matrix.GetBackend("cpu")
+	if err != nil {
+		t.Fatalf("GetBackend failed: %v", err)
+	}
+	counting := &countingBackend{Backend: cpu, name: name}
+	matrix.RegisterBackend(counting)
+	return counting
+}
+
+func TestWithBackend(t *testing.T) {
+	counting := newCountingBackend(t, "neural_counting")
+
+	nn, err := neuraln.NewDeep([]int{2, 4, 1}, neural.WithSeed(1), neural.WithBackend("neural_counting"))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	if err := nn.Train(xorInputs, xorTargets, 1); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+	trained := counting.products.Load()
+	if trained == 0 {
+		t.Fatalf("Expected training to run on the selected backend")
+	}
+
+	// Snapshots used for prediction keep the backend
+	if _, err := nn.Predict(xorInputs[0]); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if counting.products.Load() == trained {
+		t.Errorf("Expected prediction to run on the selected backend")
+	}
+	if matrix.DefaultBackend().Name() != "cpu" {
+		t.Errorf("Expected WithBackend to leave the default backend unchanged")
+	}
+
+	if _, err := neuraln.NewDeep([]int{2, 4, 1}, neural.WithBackend("missing")); !goerrors.Is(err, errors.ErrUnknownBackend) {
+		t.Errorf("Expected ErrUnknownBackend, got %v", err)
+	}
+}
+
+func TestWithBackendRunsEveryOperation(t *testing.T) {
+	counting := newCountingBackend(t, "neural_counting_all")
+	fallback := newCountingBackend(t, "neural_counting_default")
+	if err := matrix.SetDefaultBackend(fallback.Name()); err != nil {
+		t.Fatalf("SetDefaultBackend failed: %v", err)
+	}
+	defer matrix.SetDefaultBackend("cpu")
+
+	nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(1), neural.WithBackend(counting.Name()), neural.WithDropout(0, 0.25), neural.WithInitializer(0, neural.Orthogonal{}, neural.Zeros{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	if _, err := nn.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 1, BatchSize: 2}); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+	if _, err := nn.Predict(xorInputs[0]); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	if counting.products.Load() == 0 || counting.sigmoids.Load() == 0 || counting.hadamards.Load() == 0 {
+		t.Errorf("Expected products, sigmoids and Hadamard products on the selected backend, got %d, %d and %d",
+			counting.products.Load(), counting.sigmoids.Load(), counting.hadamards.Load())
+	}
+	if used := fallback.products.Load() + fallback.sigmoids.Load() + fallback.hadamards.Load(); used != 0 {
+		t.Errorf("Expected no operations on the default backend, got %d", used)
+	}
+}
@@ -0,0 +1,68 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestBatchAveragesGradients(t *testing.T) {
+	single, err := neuraln.NewDeep([]int{3, 5, 2}, neural.WithLearningRate(0.5))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	batched, err := neuraln.ImportJSON(mustExport(t, single))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	input := []float64{0.2, -0.4, 0.9}
+	target := []float64{1, 0}
+
+	// A batch of identical samples averages to the gradient of a single sample
+	if err := single.Train([][]float64{input}, [][]float64{target}, 1); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+	options := neural.TrainOptions{Epochs: 1, BatchSize: 3}
+	if _, err := batched.TrainWithOptions([][]float64{input, input, input}, [][]float64{target, target, target}, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	want, _ := single.Predict(input)
+	got, _ := batched.FeedForword(input)
+	for i, v := range got.Flatten() {
+		if math.Abs(v-want[i]) > 1e-12 {
+			t.Errorf("Expected %v, got %v", want, got.Flatten())
+			break
+		}
+	}
+}
+
+func TestTrainWithBatches(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 16, 1},
+		neural.WithOptimizer(&neural.Adam{}),
+		neural.WithLearningRate(0.05),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	inputs := [][]float64{{1, 0}, {0, 1}, {1, 1}, {0, 0}}
+	targets := [][]float64{{1}, {1}, {0}, {0}}
+
+	// The last batch of every epoch holds the remaining single sample
+	if _, err := nn.TrainWithOptions(inputs, targets, neural.TrainOptions{Epochs: 500, BatchSize: 3}); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	for i, input := range inputs {
+		predictions, err := nn.Predict(input)
+		if err != nil {
+			t.Fatalf("Predict failed: %v", err)
+		}
+		if math.Round(predictions[0]) != targets[i][0] {
+			t.Errorf("On %v: expected %v, got %v", input, targets[i][0], predictions[0])
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"math"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+// clippedStep takes one full batch step of plain gradient descent with a learning
+// rate of 1 and returns the change of every weight and bias, i.e. minus the
+// gradients after clipping, and the gradient norm reported to the callbacks.
+func clippedStep(t *testing.T, clipping *neural.Clipping) ([]float64, float64) {
+	t.Helper()
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 6, 1}, neural.WithSeed(9), neural.WithOutputActivation(neural.Linear{}))
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	before := cloneNetwork(t, n)
+
+	norm := -1.0
+	options := neural.TrainOptions{
+		Epochs:    1,
+		BatchSize: len(xorInputs),
+		Clipping:  clipping,
+		Callbacks: []neural.Callback{{OnBatchEnd: func(log neural.BatchLog) error {
+			norm = log.GradientNorm
+			return nil
+		}}},
+	}
+	if _, err := n.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	var deltas []float64
+	for i, layer := range n.Layers {
+		for _, pair := range [][2][]float64{
+			{before.Layers[i].Weights.Flatten(), layer.Weights.Flatten()},
+			{before.Layers[i].Bias.Flatten(), layer.Bias.Flatten()},
+		} {
+			for j := range pair[0] {
+				deltas = append(deltas, pair[1][j]-pair[0][j])
+			}
+		}
+	}
+	return deltas, norm
+}
+
+func l2(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value * value
+	}
+	return math.Sqrt(total)
+}
+
+func TestGradientNormReported(t *testing.T) {
+	deltas, norm := clippedStep(t, nil)
+	if math.Abs(l2(deltas)-norm) > 1e-12 {
+		t.Errorf("Expected a gradient norm of %v, got %v", l2(deltas), norm)
+	}
+}
+
+func TestClipNorm(t *testing.T) {
+	unclipped, _ := clippedStep(t, nil)
+	deltas, norm := clippedStep(t, &neural.Clipping{Norm: l2(unclipped) / 4})
+
+	if math.Abs(norm-l2(unclipped)) > 1e-12 {
+		t.Errorf("Expected the norm before clipping, %v, got %v", l2(unclipped), norm)
+	}
+	for i := range deltas {
+		if math.Abs(deltas[i]-unclipped[i]/4) > 1e-12 {
+			t.Errorf("Expected the gradients to keep their direction: %v is not a quarter of %v", deltas[i], unclipped[i])
+		}
+	}
+
+	// A norm below the threshold is left alone
+	deltas, _ = clippedStep(t, &neural.Clipping{Norm: 2 * l2(unclipped)})
+	for i := range deltas {
+		if deltas[i] != unclipped[i] {
+			t.Errorf("Expected gradient %v to be left alone, got %v", unclipped[i], deltas[i])
+		}
+	}
+}
+
+func TestClipValue(t *testing.T) {
+	unclipped, _ := clippedStep(t, nil)
+	const limit = 0.01
+	deltas, _ := clippedStep(t, &neural.Clipping{Value: limit})
+
+	for i := range deltas {
+		expected := math.Max(-limit, math.Min(limit, unclipped[i]))
+		if math.Abs(deltas[i]-expected) > 1e-12 {
+			t.Errorf("Expected gradient %v to be clipped to %v, got %v", unclipped[i], expected, deltas[i])
+		}
+	}
+}
+
+func TestClippingValidation(t *testing.T) {
+	n := neural.Neural{}
+	n.Create(2, 2, 1)
+	for _, clipping := range []*neural.Clipping{{Value: -1}, {Norm: -1}} {
+		_, err := n.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 1, Clipping: clipping})
+		if !goerrors.Is(err, errors.ErrClipping) {
+			t.Errorf("Expected %v for %+v, got %v", errors.ErrClipping, *clipping, err)
+		}
+	}
+}
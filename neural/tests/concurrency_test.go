@@ -0,0 +1,224 @@
+package neural_test
+
+import (
+	"bytes"
+	"neuraln"
+	"neuraln/neural"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Run with -race to check that predicting while training is free of data races.
+func TestPredictWhileTraining(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 16, 1}, neural.WithHiddenDropout(0.1), neural.WithOptimizer(&neural.Adam{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for !done.Load() {
+				if _, err := nn.Predict(xorInputs[i]); err != nil {
+					t.Errorf("Predict failed: %v", err)
+					return
+				}
+				if _, err := nn.Evaluate(xorInputs, xorTargets); err != nil {
+					t.Errorf("Evaluate failed: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := nn.ExportJSON(); err != nil {
+			t.Errorf("ExportJSON failed: %v", err)
+		}
+	}()
+
+	if _, err := nn.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 50, BatchSize: 2}); err != nil {
+		t.Errorf("TrainWithOptions failed: %v", err)
+	}
+	if err := nn.Train(xorInputs, xorTargets, 10); err != nil {
+		t.Errorf("Train failed: %v", err)
+	}
+	done.Store(true)
+	wg.Wait()
+}
+
+func TestPredictSeesEveryEpoch(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(1))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	initial, _ := nn.Predict(xorInputs[1])
+
+	var during []float64
+	options := neural.TrainOptions{Epochs: 1, Callbacks: []neural.Callback{{OnEpochEnd: func(neural.EpochLog) error {
+		during, _ = nn.Predict(xorInputs[1])
+		return nil
+	}}}}
+	if _, err := nn.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+	after, _ := nn.Predict(xorInputs[1])
+
+	if during[0] == initial[0] {
+		t.Errorf("Expected the weights of the epoch to be published before the callbacks run")
+	}
+	if after[0] != during[0] {
+		t.Errorf("Expected %v after training, got %v", during[0], after[0])
+	}
+
+	// Predictions match the exported network
+	n, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	expected, _ := n.FeedForword(xorInputs[1])
+	if expected.Matrix[0][0] != after[0] {
+		t.Errorf("Expected %v, got %v", expected.Matrix[0][0], after[0])
+	}
+}
+
+func TestExportJSONWhileTraining(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(1), neural.WithOptimizer(&neural.Adam{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var predictions []float64
+	started, finished := make(chan struct{}), make(chan struct{})
+	defer func() { <-finished }()
+	go func() {
+		defer close(finished)
+		options := neural.TrainOptions{Epochs: 200, Callbacks: []neural.Callback{{OnEpochEnd: func(log neural.EpochLog) error {
+			prediction, _ := nn.Predict(xorInputs[1])
+			mu.Lock()
+			predictions = append(predictions, prediction[0])
+			mu.Unlock()
+			if log.Epoch == 0 {
+				close(started)
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		}}}}
+		if _, err := nn.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+			t.Errorf("TrainWithOptions failed: %v", err)
+		}
+	}()
+
+	// The export holds the weights of an epoch and does not wait for the others
+	<-started
+	data := mustExport(t, nn)
+	select {
+	case <-finished:
+		t.Errorf("Expected ExportJSON to return while training goes on")
+	default:
+	}
+	n, err := neuraln.ImportJSON(data)
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	exported, _ := n.FeedForword(xorInputs[1])
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, prediction := range predictions {
+		if prediction == exported.Matrix[0][0] {
+			return
+		}
+	}
+	t.Errorf("Expected the export to hold the weights of an epoch, got a prediction of %v", exported.Matrix[0][0])
+}
+
+func TestCallbacksUseNetwork(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(1), neural.WithOptimizer(&neural.Adam{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	var checkpoint []byte
+	var history *neural.History
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		options := neural.TrainOptions{Epochs: 10, Callbacks: []neural.Callback{{OnEpochEnd: func(log neural.EpochLog) error {
+			grads, err := nn.Gradients(xorInputs, xorTargets)
+			if err != nil {
+				return err
+			}
+			if err := nn.ApplyGradients(grads, nil); err != nil {
+				return err
+			}
+			data, err := nn.ExportJSON()
+			if err != nil {
+				return err
+			}
+			if log.Epoch == 0 {
+				checkpoint = data
+			}
+			if log.Epoch == 2 {
+				return nn.LoadJSON(checkpoint)
+			}
+			return nil
+		}}}}
+		var err error
+		if history, err = nn.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+			t.Errorf("TrainWithOptions failed: %v", err)
+		}
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Expected the callbacks to use the network without waiting for training")
+	}
+	if history == nil {
+		return
+	}
+
+	// Restoring the checkpoint stops the training of the replaced network
+	if len(history.Epochs) != 3 || !history.StoppedEarly {
+		t.Errorf("Expected training to stop after 3 epochs, got %d", len(history.Epochs))
+	}
+	if !bytes.Equal(checkpoint, mustExport(t, nn)) {
+		t.Errorf("Expected the network of the checkpoint after training")
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	trained, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(1), neural.WithOptimizer(&neural.Adam{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	if err := trained.Train(xorInputs, xorTargets, 5); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+	data := mustExport(t, trained)
+
+	nn := neuraln.New(2, 3, 1)
+	if err := nn.LoadJSON(data); err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	expected, _ := trained.Predict(xorInputs[1])
+	if actual, _ := nn.Predict(xorInputs[1]); actual[0] != expected[0] {
+		t.Errorf("Expected the loaded network to predict %v, got %v", expected[0], actual[0])
+	}
+	if !bytes.Equal(data, mustExport(t, nn)) {
+		t.Errorf("Expected the loaded network to export the same model")
+	}
+
+	if err := nn.LoadJSON([]byte("{")); err == nil {
+		t.Errorf("Expected an error for invalid JSON")
+	}
+}
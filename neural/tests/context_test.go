@@ -0,0 +1,85 @@
+package neural_test
+
+import (
+	"context"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+	"time"
+)
+
+func TestTrainContextCancel(t *testing.T) {
+	nn := neuraln.New(2, 4, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	steps := 0
+	options := neural.TrainOptions{
+		Epochs: 100,
+		Callbacks: []neural.Callback{{OnBatchEnd: func(log neural.BatchLog) error {
+			steps++
+			if log.Step == 9 {
+				cancel()
+			}
+			return nil
+		}}},
+	}
+
+	history, err := nn.TrainContext(ctx, xorInputs, xorTargets, options)
+	if err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+	if steps != 10 {
+		t.Errorf("Expected training to stop right after the 10th step, got %d steps", steps)
+	}
+	if len(history.Epochs) != 2 {
+		t.Errorf("Expected 2 completed epochs, got %d", len(history.Epochs))
+	}
+
+	// The partially trained network can still be exported and used
+	imported, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	want, _ := nn.Predict(xorInputs[0])
+	got, err := imported.FeedForword(xorInputs[0])
+	if err != nil || got.Flatten()[0] != want[0] {
+		t.Errorf("Expected the exported network to predict %v, got %v (%v)", want, got, err)
+	}
+}
+
+func TestTrainContextAlreadyDone(t *testing.T) {
+	nn := neuraln.New(2, 4, 1)
+	before := mustExport(t, nn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	history, err := nn.TrainContext(ctx, xorInputs, xorTargets, neural.TrainOptions{Epochs: 10})
+	if err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+	if len(history.Epochs) != 0 {
+		t.Errorf("Expected no completed epochs, got %d", len(history.Epochs))
+	}
+	if string(mustExport(t, nn)) != string(before) {
+		t.Errorf("Expected the network to be unchanged")
+	}
+}
+
+func TestTrainContextDeadline(t *testing.T) {
+	nn := neuraln.New(2, 64, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := nn.TrainContext(ctx, xorInputs, xorTargets, neural.TrainOptions{Epochs: 1 << 30})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected training to return promptly after the deadline, took %v", elapsed)
+	}
+}
@@ -0,0 +1,72 @@
+package neural_test
+
+import (
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestCreateDeep(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{4, 8, 6, 3})
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+
+	if len(n.Layers) != 3 {
+		t.Fatalf("Expected 3 layers, got %d", len(n.Layers))
+	}
+
+	shapes := [][2]int{{8, 4}, {6, 8}, {3, 6}}
+	for i, layer := range n.Layers {
+		if layer.Weights.Row != shapes[i][0] || layer.Weights.Col != shapes[i][1] {
+			t.Errorf("Layer %d: expected weights %dx%d, got %dx%d", i, shapes[i][0], shapes[i][1], layer.Weights.Row, layer.Weights.Col)
+		}
+		if layer.Bias.Row != shapes[i][0] || layer.Bias.Col != 1 {
+			t.Errorf("Layer %d: expected bias %dx1, got %dx%d", i, shapes[i][0], layer.Bias.Row, layer.Bias.Col)
+		}
+	}
+
+	output, err := n.FeedForword([]float64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("FeedForword failed: %v", err)
+	}
+	if output.Row != 3 || output.Col != 1 {
+		t.Errorf("Expected output 3x1, got %dx%d", output.Row, output.Col)
+	}
+}
+
+func TestCreateDeepInvalidSizes(t *testing.T) {
+	for _, sizes := range [][]int{nil, {3}, {3, 0, 1}} {
+		if _, err := neuraln.NewDeep(sizes); err != errors.ErrInvalidLayerSizes {
+			t.Errorf("NewDeep(%v): expected %v, got %v", sizes, errors.ErrInvalidLayerSizes, err)
+		}
+	}
+}
+
+func TestTrainDeep(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 6, 1})
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	inputsData := [][]float64{
+		{1, 0}, {0, 1}, {1, 1}, {0, 0},
+	}
+
+	outputsData := [][]float64{
+		{1}, {1}, {0}, {0},
+	}
+
+	if err := nn.Train(inputsData, outputsData, 10); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	predictions, err := nn.Predict([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if len(predictions) != 1 {
+		t.Errorf("Expected 1 prediction, got %d", len(predictions))
+	}
+}
@@ -0,0 +1,127 @@
+package neural_test
+
+import (
+	"bytes"
+	goerrors "errors"
+	"math/rand/v2"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestDropoutOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		option neural.Option
+		err    error
+	}{
+		{"OutputLayer", neural.WithDropout(1, 0.5), errors.ErrLayerOutOfRange},
+		{"NegativeRate", neural.WithDropout(0, -0.1), errors.ErrDropoutRate},
+		{"RateOfOne", neural.WithHiddenDropout(1), errors.ErrDropoutRate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := neuraln.NewDeep([]int{2, 4, 1}, test.option)
+			if !goerrors.Is(err, test.err) {
+				t.Errorf("Expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestDropoutDisabledAtInference(t *testing.T) {
+	plain, err := neuraln.NewDeep([]int{2, 16, 1}, neural.WithSeed(1))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	dropped, err := neuraln.NewDeep([]int{2, 16, 1}, neural.WithSeed(1), neural.WithHiddenDropout(0.5))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	for _, input := range xorInputs {
+		expected, _ := plain.Predict(input)
+		first, _ := dropped.Predict(input)
+		second, _ := dropped.Predict(input)
+		if expected[0] != first[0] || first[0] != second[0] {
+			t.Errorf("Expected dropout to be disabled in Predict for %v", input)
+		}
+	}
+}
+
+func TestDropoutTraining(t *testing.T) {
+	train := func(options ...neural.Option) []byte {
+		nn, err := neuraln.NewDeep([]int{2, 16, 1}, append([]neural.Option{neural.WithSeed(3)}, options...)...)
+		if err != nil {
+			t.Fatalf("NewDeep failed: %v", err)
+		}
+		if _, err := nn.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 10, BatchSize: 2}); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+		return mustExport(t, nn)
+	}
+
+	first := train(neural.WithHiddenDropout(0.5))
+	if !bytes.Equal(first, train(neural.WithHiddenDropout(0.5))) {
+		t.Errorf("Expected seeded dropout to be reproducible")
+	}
+	if bytes.Equal(first, train(neural.WithHiddenDropout(0))) {
+		t.Errorf("Expected dropout to change the training")
+	}
+}
+
+func TestDropoutUsesNetworkRandom(t *testing.T) {
+	// Two networks with the same weights but differently seeded generators
+	networks := make([]*neural.Neural, 2)
+	for i := range networks {
+		n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 1}, neural.WithSeed(uint64(i)), neural.WithHiddenDropout(0.5))
+		if err != nil {
+			t.Fatalf("CreateDeep failed: %v", err)
+		}
+		networks[i] = n
+	}
+	for i, layer := range networks[0].Layers {
+		networks[1].Layers[i].Weights = layer.Weights.Copy()
+		networks[1].Layers[i].Bias = layer.Bias.Copy()
+	}
+
+	// The same Source shuffles both alike, the masks differ
+	for _, n := range networks {
+		options := neural.TrainOptions{Epochs: 2, Source: rand.NewPCG(7, 7)}
+		if _, err := n.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+	}
+	a, _ := networks[0].ExportJSON()
+	b, _ := networks[1].ExportJSON()
+	if bytes.Equal(a, b) {
+		t.Errorf("Expected the dropout masks to come from the network's generator, not from Source")
+	}
+}
+
+func TestDropoutPersisted(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 8, 1}, neural.WithDropout(1, 0.25))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	n, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	for i, expected := range []float64{0, 0.25, 0} {
+		if n.Layers[i].Dropout != expected {
+			t.Errorf("Layer %d: expected dropout %v, got %v", i, expected, n.Layers[i].Dropout)
+		}
+	}
+
+	for _, input := range xorInputs {
+		expected, _ := nn.Predict(input)
+		actual, _ := n.FeedForword(input)
+		if expected[0] != actual.Matrix[0][0] {
+			t.Errorf("Expected the imported network to predict %v, got %v", expected[0], actual.Matrix[0][0])
+		}
+	}
+}
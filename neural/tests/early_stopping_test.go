@@ -0,0 +1,135 @@
+package neural_test
+
+import (
+	"bytes"
+	"math"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+// ascendingScheduler uses the base rate for the first epoch and climbs the
+// gradient afterwards, so every later epoch makes the network worse.
+type ascendingScheduler struct{}
+
+func (ascendingScheduler) LearningRate(base float64, epoch, step int) float64 {
+	if epoch == 0 {
+		return base
+	}
+	return -base
+}
+
+func TestValidationSplit(t *testing.T) {
+	nn := neuraln.New(1, 2, 1)
+
+	var inputs, targets [][]float64
+	for i := 0; i < 10; i++ {
+		inputs = append(inputs, []float64{float64(i) / 10})
+		targets = append(targets, []float64{float64(i % 2)})
+	}
+
+	steps := 0
+	options := neural.TrainOptions{
+		Epochs:          2,
+		ValidationSplit: 0.2,
+		Callbacks:       []neural.Callback{{OnBatchEnd: func(neural.BatchLog) error { steps++; return nil }}},
+	}
+	history, err := nn.TrainWithOptions(inputs, targets, options)
+	if err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	if steps != 16 {
+		t.Errorf("Expected 8 training samples per epoch, got %d steps in 2 epochs", steps)
+	}
+	if !history.Epochs[0].Validated {
+		t.Errorf("Expected the held out samples to be validated")
+	}
+
+	options.ValidationSplit = 0.05
+	if _, err := nn.TrainWithOptions(inputs, targets, options); err != errors.ErrValidationSplit {
+		t.Errorf("Expected %v, got %v", errors.ErrValidationSplit, err)
+	}
+}
+
+func TestEarlyStoppingPatience(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 2, 1}, neural.WithLearningRate(0))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	// Without learning the loss never improves after the first epoch
+	options := neural.TrainOptions{
+		Epochs:        20,
+		EarlyStopping: &neural.EarlyStopping{Patience: 3, MinDelta: 1e-9},
+	}
+	history, err := nn.TrainWithOptions(xorInputs, xorTargets, options)
+	if err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	if len(history.Epochs) != 4 || !history.StoppedEarly || history.BestEpoch != 0 {
+		t.Errorf("Expected to stop after 4 epochs with the first one as best, got %d epochs, stopped early %v, best epoch %d",
+			len(history.Epochs), history.StoppedEarly, history.BestEpoch)
+	}
+}
+
+func TestEarlyStoppingRestoresBestWeights(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{1, 4, 1},
+		neural.WithOutputActivation(neural.Linear{}),
+		neural.WithLearningRate(0.05),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	inputs := [][]float64{{-1}, {-0.5}, {0}, {0.5}, {1}}
+	targets := [][]float64{{-2}, {-1}, {0}, {1}, {2}}
+
+	options := neural.TrainOptions{
+		Epochs:            50,
+		Scheduler:         ascendingScheduler{},
+		ValidationInputs:  inputs,
+		ValidationTargets: targets,
+		EarlyStopping:     &neural.EarlyStopping{Patience: 2, RestoreBestWeights: true},
+	}
+	history, err := nn.TrainWithOptions(inputs, targets, options)
+	if err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	if history.BestEpoch != 0 || len(history.Epochs) != 3 {
+		t.Fatalf("Expected to stop after 3 epochs with the first one as best, got %d epochs and best epoch %d", len(history.Epochs), history.BestEpoch)
+	}
+
+	loss, err := nn.Evaluate(inputs, targets)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if best := history.Epochs[0].ValidationLoss; math.Abs(loss-best) > 1e-12 {
+		t.Errorf("Expected the weights of the best epoch with loss %v, got loss %v", best, loss)
+	}
+}
+
+func TestEarlyStoppingMonitorsMetric(t *testing.T) {
+	nn := neuraln.New(2, 2, 1)
+
+	options := neural.TrainOptions{
+		Epochs:        5,
+		EarlyStopping: &neural.EarlyStopping{Monitor: "val_accuracy", Maximize: true},
+	}
+	before := mustExport(t, nn)
+	if _, err := nn.TrainWithOptions(xorInputs, xorTargets, options); err != errors.ErrUnknownMonitor {
+		t.Errorf("Expected %v, got %v", errors.ErrUnknownMonitor, err)
+	}
+	if !bytes.Equal(before, mustExport(t, nn)) {
+		t.Errorf("Expected an unknown monitor to be reported before the first training step")
+	}
+
+	options.Metrics = []neural.Metric{neural.Accuracy{}}
+	options.ValidationInputs, options.ValidationTargets = xorInputs, xorTargets
+	if _, err := nn.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+		t.Errorf("TrainWithOptions failed: %v", err)
+	}
+}
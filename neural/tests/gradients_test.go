@@ -0,0 +1,143 @@
+package neural_test
+
+import (
+	"bytes"
+	goerrors "errors"
+	"math"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+func assertClose(t *testing.T, name string, expected, actual *matrix.Matrix) {
+	t.Helper()
+	for i := range expected.Matrix {
+		for j := range expected.Matrix[i] {
+			if math.Abs(expected.Matrix[i][j]-actual.Matrix[i][j]) > 1e-12 {
+				t.Errorf("%s[%d][%d]: expected %v, got %v", name, i, j, expected.Matrix[i][j], actual.Matrix[i][j])
+			}
+		}
+	}
+}
+
+func TestGradientsDoNotMutate(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(2))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	before := mustExport(t, nn)
+
+	grads, err := nn.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	if len(grads.Layers) != 2 || grads.Layers[0].Weights.Row != 8 || grads.Layers[0].Weights.Col != 2 || grads.Layers[1].Bias.Row != 1 {
+		t.Errorf("Expected the gradients to mirror the layers of the network")
+	}
+	if !bytes.Equal(before, mustExport(t, nn)) {
+		t.Errorf("Expected Gradients to leave the network unchanged")
+	}
+
+	loss, _ := nn.Evaluate(xorInputs, xorTargets)
+	if math.Abs(grads.Loss-loss) > 1e-12 {
+		t.Errorf("Expected loss %v, got %v", loss, grads.Loss)
+	}
+}
+
+func TestGradientsKeepRandomState(t *testing.T) {
+	networks := make([]*neuraln.NeuralNetwork, 2)
+	for i := range networks {
+		nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSeed(6), neural.WithHiddenDropout(0.5))
+		if err != nil {
+			t.Fatalf("NewDeep failed: %v", err)
+		}
+		networks[i] = nn
+	}
+
+	// Drawing the dropout masks of Gradients does not change what training draws
+	if _, err := networks[0].Gradients(xorInputs, xorTargets); err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	for _, nn := range networks {
+		if err := nn.Train(xorInputs, xorTargets, 2); err != nil {
+			t.Fatalf("Train failed: %v", err)
+		}
+	}
+	if !bytes.Equal(mustExport(t, networks[0]), mustExport(t, networks[1])) {
+		t.Errorf("Expected Gradients to leave the random number generator unchanged")
+	}
+}
+
+func TestApplyGradientsMatchesTrain(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 1}, neural.WithSeed(4), neural.WithLearningRate(0.5))
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	trained := cloneNetwork(t, n)
+	if _, err := trained.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 1, BatchSize: len(xorInputs)}); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	grads, err := n.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	if err := n.ApplyGradients(grads, nil); err != nil {
+		t.Fatalf("ApplyGradients failed: %v", err)
+	}
+
+	for i, layer := range n.Layers {
+		assertClose(t, "weights", trained.Layers[i].Weights, layer.Weights)
+		assertClose(t, "bias", trained.Layers[i].Bias, layer.Bias)
+	}
+}
+
+func TestGradientsAccumulation(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 1}, neural.WithSeed(6))
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+
+	full, err := n.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	accumulated, _ := n.Gradients(xorInputs[:2], xorTargets[:2])
+	second, _ := n.Gradients(xorInputs[2:], xorTargets[2:])
+	if err := accumulated.Add(second); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	accumulated.Scale(0.5)
+
+	for i := range full.Layers {
+		assertClose(t, "weights", full.Layers[i].Weights, accumulated.Layers[i].Weights)
+		assertClose(t, "bias", full.Layers[i].Bias, accumulated.Layers[i].Bias)
+	}
+	if math.Abs(full.Loss-accumulated.Loss) > 1e-12 {
+		t.Errorf("Expected loss %v, got %v", full.Loss, accumulated.Loss)
+	}
+}
+
+func TestGradientsMismatch(t *testing.T) {
+	small, _ := (&neural.Neural{}).CreateDeep([]int{2, 4, 1})
+	large, _ := (&neural.Neural{}).CreateDeep([]int{2, 8, 1})
+	normalized, _ := (&neural.Neural{}).CreateDeep([]int{2, 4, 1}, neural.WithNormalization(0, neural.LayerNorm{}))
+
+	grads, err := small.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	other, _ := large.Gradients(xorInputs, xorTargets)
+
+	if err := large.ApplyGradients(grads, nil); !goerrors.Is(err, errors.ErrGradientsMismatch) {
+		t.Errorf("Expected %v, got %v", errors.ErrGradientsMismatch, err)
+	}
+	if err := normalized.ApplyGradients(grads, nil); !goerrors.Is(err, errors.ErrGradientsMismatch) {
+		t.Errorf("Expected %v for missing Gamma and Beta, got %v", errors.ErrGradientsMismatch, err)
+	}
+	if err := grads.Add(other); !goerrors.Is(err, errors.ErrGradientsMismatch) {
+		t.Errorf("Expected %v, got %v", errors.ErrGradientsMismatch, err)
+	}
+}
@@ -0,0 +1,143 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"fmt"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+var (
+	xorInputs  = [][]float64{{1, 0}, {0, 1}, {1, 1}, {0, 0}}
+	xorTargets = [][]float64{{1}, {1}, {0}, {0}}
+)
+
+func TestTrainHistory(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 8, 1},
+		neural.WithOptimizer(&neural.Adam{}),
+		neural.WithLearningRate(0.05),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	batches := 0
+	options := neural.TrainOptions{
+		Epochs:            200,
+		BatchSize:         2,
+		Metrics:           []neural.Metric{neural.Accuracy{}},
+		ValidationInputs:  xorInputs,
+		ValidationTargets: xorTargets,
+		Callbacks: []neural.Callback{{
+			OnBatchEnd: func(log neural.BatchLog) error {
+				if log.Step != batches || log.Batch != batches%2 || log.LearningRate != 0.05 {
+					t.Errorf("Unexpected batch log %+v after %d batches", log, batches)
+				}
+				batches++
+				return nil
+			},
+		}},
+	}
+
+	history, err := nn.TrainWithOptions(xorInputs, xorTargets, options)
+	if err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	if len(history.Epochs) != 200 || batches != 400 {
+		t.Fatalf("Expected 200 epochs and 400 batches, got %d and %d", len(history.Epochs), batches)
+	}
+
+	losses := history.Loss()
+	if losses[len(losses)-1] >= losses[0] {
+		t.Errorf("Expected the training loss to decrease, got %v then %v", losses[0], losses[len(losses)-1])
+	}
+
+	last := history.Epochs[len(history.Epochs)-1]
+	if !last.Validated || last.ValidationMetrics["accuracy"] != 1 {
+		t.Errorf("Expected a validation accuracy of 1, got %+v", last)
+	}
+	if _, ok := last.Metrics["accuracy"]; !ok {
+		t.Errorf("Expected the training accuracy to be recorded, got %+v", last.Metrics)
+	}
+	if last.LearningRate != 0.05 {
+		t.Errorf("Expected a learning rate of 0.05, got %v", last.LearningRate)
+	}
+}
+
+func TestCallbackStopsTraining(t *testing.T) {
+	for _, stop := range []error{errors.ErrStopTraining, fmt.Errorf("target reached: %w", errors.ErrStopTraining)} {
+		nn := neuraln.New(2, 2, 1)
+
+		options := neural.TrainOptions{
+			Epochs: 10,
+			Callbacks: []neural.Callback{
+				{OnEpochEnd: func(log neural.EpochLog) error {
+					if log.Epoch == 2 {
+						return stop
+					}
+					return nil
+				}},
+				{OnEpochEnd: func(log neural.EpochLog) error { return nil }},
+			},
+		}
+
+		history, err := nn.TrainWithOptions(xorInputs, xorTargets, options)
+		if err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+		if len(history.Epochs) != 3 {
+			t.Errorf("%v: expected training to stop after 3 epochs, got %d", stop, len(history.Epochs))
+		}
+	}
+}
+
+func TestCallbackErrorAbortsTraining(t *testing.T) {
+	nn := neuraln.New(2, 2, 1)
+
+	failure := goerrors.New("checkpoint failed")
+	options := neural.TrainOptions{
+		Epochs: 10,
+		Callbacks: []neural.Callback{{OnBatchEnd: func(log neural.BatchLog) error {
+			if log.Step == 5 {
+				return failure
+			}
+			return nil
+		}}},
+	}
+
+	history, err := nn.TrainWithOptions(xorInputs, xorTargets, options)
+	if err != failure {
+		t.Fatalf("Expected %v, got %v", failure, err)
+	}
+	if len(history.Epochs) != 1 {
+		t.Errorf("Expected 1 completed epoch, got %d", len(history.Epochs))
+	}
+}
+
+func TestAccuracy(t *testing.T) {
+	outputs := matrix.NewFromColumns([][]float64{{0.1, 0.7, 0.2}, {0.5, 0.3, 0.2}, {0.3, 0.3, 0.4}})
+	targets := matrix.NewFromColumns([][]float64{{0, 1, 0}, {0, 0, 1}, {0, 0, 1}})
+
+	accuracy, err := neural.Accuracy{}.Measure(outputs, targets)
+	if err != nil {
+		t.Fatalf("Measure failed: %v", err)
+	}
+	if accuracy != 2.0/3 {
+		t.Errorf("Expected an accuracy of 2/3, got %v", accuracy)
+	}
+
+	outputs = matrix.NewFromColumns([][]float64{{0.9}, {0.4}, {0.6}, {0.1}})
+	targets = matrix.NewFromColumns([][]float64{{1}, {1}, {0}, {0}})
+
+	accuracy, err = neural.Accuracy{}.Measure(outputs, targets)
+	if err != nil {
+		t.Fatalf("Measure failed: %v", err)
+	}
+	if accuracy != 0.5 {
+		t.Errorf("Expected an accuracy of 0.5, got %v", accuracy)
+	}
+}
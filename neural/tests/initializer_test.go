@@ -0,0 +1,115 @@
+package neural_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestInitializerVariance(t *testing.T) {
+	const fanIn, fanOut = 300, 200
+
+	tests := []struct {
+		initializer neural.Initializer
+		variance    float64
+	}{
+		{neural.XavierUniform{}, 2.0 / (fanIn + fanOut)},
+		{neural.XavierNormal{}, 2.0 / (fanIn + fanOut)},
+		{neural.HeUniform{}, 2.0 / fanIn},
+		{neural.HeNormal{}, 2.0 / fanIn},
+		{neural.LeCunUniform{}, 1.0 / fanIn},
+		{neural.LeCunNormal{}, 1.0 / fanIn},
+		{neural.RandomUniform{Low: -1, High: 1}, 1.0 / 3},
+		{neural.RandomNormal{StdDev: 0.1}, 0.01},
+	}
+
+	r := rand.New(rand.NewPCG(1, 1))
+	for _, test := range tests {
+		values := test.initializer.Initialize(r, fanOut, fanIn, fanIn, fanOut).Flatten()
+		if len(values) != fanIn*fanOut {
+			t.Fatalf("%T: expected %d values, got %d", test.initializer, fanIn*fanOut, len(values))
+		}
+
+		variance := 0.0
+		for _, v := range values {
+			variance += v * v
+		}
+		variance /= float64(len(values))
+
+		if math.Abs(variance-test.variance)/test.variance > 0.05 {
+			t.Errorf("%T: expected variance %v, got %v", test.initializer, test.variance, variance)
+		}
+	}
+}
+
+func TestOrthogonal(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 1))
+
+	for _, shape := range [][2]int{{4, 6}, {6, 4}, {5, 5}} {
+		rows, cols := shape[0], shape[1]
+		m := neural.Orthogonal{Gain: 2}.Initialize(r, rows, cols, cols, rows)
+
+		// The shorter side must be orthogonal with norm Gain
+		vectors, length := rows, cols
+		at := func(v, i int) float64 { return m.Matrix[v][i] }
+		if rows > cols {
+			vectors, length = cols, rows
+			at = func(v, i int) float64 { return m.Matrix[i][v] }
+		}
+
+		for a := 0; a < vectors; a++ {
+			for b := 0; b < vectors; b++ {
+				dot := 0.0
+				for i := 0; i < length; i++ {
+					dot += at(a, i) * at(b, i)
+				}
+				expected := 0.0
+				if a == b {
+					expected = 4
+				}
+				if math.Abs(dot-expected) > 1e-9 {
+					t.Errorf("%dx%d: expected dot product %v between %d and %d, got %v", rows, cols, expected, a, b, dot)
+				}
+			}
+		}
+	}
+}
+
+func TestWithInitializer(t *testing.T) {
+	n, err := neuraln.NewDeep([]int{3, 4, 2},
+		neural.WithInitializers(neural.XavierUniform{}, neural.Zeros{}),
+		neural.WithInitializer(1, neural.Constant{Value: 0.5}, neural.Constant{Value: -1}),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	imported, err := neuraln.ImportJSON(mustExport(t, n))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	limit := math.Sqrt(6.0 / 7)
+	for _, v := range imported.Layers[0].Weights.Flatten() {
+		if math.Abs(v) > limit {
+			t.Errorf("Expected Xavier weights within %v, got %v", limit, v)
+		}
+	}
+	for _, v := range imported.Layers[0].Bias.Flatten() {
+		if v != 0 {
+			t.Errorf("Expected zero biases, got %v", v)
+		}
+	}
+	for _, v := range imported.Layers[1].Weights.Flatten() {
+		if v != 0.5 {
+			t.Errorf("Expected constant weights 0.5, got %v", v)
+		}
+	}
+	for _, v := range imported.Layers[1].Bias.Flatten() {
+		if v != -1 {
+			t.Errorf("Expected constant biases -1, got %v", v)
+		}
+	}
+}
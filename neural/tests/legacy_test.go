@@ -0,0 +1,60 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"math"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+// legacyModel is a {2, 2, 1} network as exported before networks had layers.
+const legacyModel = `{
+	"InputNodes": 2,
+	"OutputNodes": 1,
+	"WeightIH": {"Matrix": [[0.5, -0.25], [0.1, 0.2]], "Col": 2, "Row": 2},
+	"WeightHO": {"Matrix": [[1.5, -2]], "Col": 2, "Row": 1},
+	"BiasH": {"Matrix": [[0.1], [-0.3]], "Col": 1, "Row": 2},
+	"BiasO": {"Matrix": [[0.05]], "Col": 1, "Row": 1},
+	"LearningRate": 0.1
+}`
+
+func TestImportLegacyModel(t *testing.T) {
+	n, err := neuraln.ImportJSON([]byte(legacyModel))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	if len(n.Layers) != 2 {
+		t.Fatalf("Expected the legacy weights to become 2 layers, got %d", len(n.Layers))
+	}
+	if _, ok := n.Loss.(neural.BinaryCrossEntropy); !ok {
+		t.Errorf("Expected the legacy model to minimize BinaryCrossEntropy, got %v", n.Loss)
+	}
+
+	sigmoid := func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+	h1 := sigmoid(0.5*0.3 - 0.25*0.7 + 0.1)
+	h2 := sigmoid(0.1*0.3 + 0.2*0.7 - 0.3)
+	expected := sigmoid(1.5*h1 - 2*h2 + 0.05)
+
+	outputs, err := n.FeedForword([]float64{0.3, 0.7})
+	if err != nil {
+		t.Fatalf("FeedForword failed: %v", err)
+	}
+	if outputs.Row != 1 || outputs.Col != 1 || math.Abs(outputs.At(0, 0)-expected) > 1e-12 {
+		t.Errorf("Expected [%v], got %v", expected, outputs.Flatten())
+	}
+}
+
+func TestNetworkWithoutLayers(t *testing.T) {
+	n, err := neuraln.ImportJSON([]byte(`{"InputNodes": 2, "OutputNodes": 1}`))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	if _, err := n.FeedForword([]float64{0.3, 0.7}); !goerrors.Is(err, errors.ErrNoLayers) {
+		t.Errorf("Expected ErrNoLayers from FeedForword, got %v", err)
+	}
+	if _, err := n.TrainWithOptions([][]float64{{0, 1}}, [][]float64{{1}}, neural.TrainOptions{Epochs: 1}); !goerrors.Is(err, errors.ErrNoLayers) {
+		t.Errorf("Expected ErrNoLayers from TrainWithOptions, got %v", err)
+	}
+}
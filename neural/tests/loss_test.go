@@ -0,0 +1,163 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestLossGradients(t *testing.T) {
+	outputs := matrix.New(3, 2)
+	outputs.Matrix = [][]float64{
+		{0.2, 0.6},
+		{0.7, 0.1},
+		{0.1, 0.3},
+	}
+
+	targets := matrix.New(3, 2)
+	targets.Matrix = [][]float64{
+		{0, 1},
+		{1, 0},
+		{0, 0},
+	}
+
+	losses := []neural.Loss{
+		neural.MeanSquaredError{}, neural.MeanAbsoluteError{}, neural.Huber{Delta: 0.25},
+		neural.BinaryCrossEntropy{}, neural.CategoricalCrossEntropy{},
+	}
+
+	const h = 1e-6
+	for _, loss := range losses {
+		grad, err := loss.Gradient(outputs, targets)
+		if err != nil {
+			t.Fatalf("%s: Gradient failed: %v", loss.Name(), err)
+		}
+
+		for i := 0; i < outputs.Row; i++ {
+			for j := 0; j < outputs.Col; j++ {
+				original := outputs.Matrix[i][j]
+				outputs.Matrix[i][j] = original + h
+				plus, _ := loss.Loss(outputs, targets)
+				outputs.Matrix[i][j] = original - h
+				minus, _ := loss.Loss(outputs, targets)
+				outputs.Matrix[i][j] = original
+
+				numeric := (plus - minus) / (2 * h)
+				if math.Abs(numeric-grad.Matrix[i][j]) > 1e-5 {
+					t.Errorf("%s at (%d, %d): expected gradient %v, got %v", loss.Name(), i, j, numeric, grad.Matrix[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestLossValues(t *testing.T) {
+	outputs := matrix.NewFromArray([]float64{0.5, 3})
+	targets := matrix.NewFromArray([]float64{0, 0})
+
+	tests := []struct {
+		loss     neural.Loss
+		expected float64
+	}{
+		{neural.MeanSquaredError{}, 0.5 * (0.25 + 9)},
+		{neural.MeanAbsoluteError{}, 3.5},
+		{neural.Huber{}, 0.125 + 2.5},
+	}
+
+	for _, test := range tests {
+		loss, err := test.loss.Loss(outputs, targets)
+		if err != nil {
+			t.Fatalf("%s: Loss failed: %v", test.loss.Name(), err)
+		}
+		if math.Abs(loss-test.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.loss.Name(), test.expected, loss)
+		}
+	}
+}
+
+func TestRegressionWithLinearOutput(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{1, 8, 1},
+		neural.WithHiddenActivation(neural.Tanh{}),
+		neural.WithOutputActivation(neural.Linear{}),
+		neural.WithLoss(neural.MeanSquaredError{}),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	// y = 2x + 3 has targets well outside the (0, 1) range of a sigmoid
+	var inputs, targets [][]float64
+	for x := -1.0; x <= 1; x += 0.25 {
+		inputs = append(inputs, []float64{x})
+		targets = append(targets, []float64{2*x + 3})
+	}
+
+	trained, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	trained.LearningRate = 0.05
+	if err := trained.Train(inputs, targets, 300); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	loss, err := trained.Evaluate(inputs, targets)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if loss > 0.01 {
+		t.Errorf("Expected the regression loss to drop below 0.01, got %v", loss)
+	}
+}
+
+func TestLossExportImport(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 2, 1}, neural.WithLoss(neural.Huber{Delta: 0.5}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	imported, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	if imported.Loss != (neural.Huber{Delta: 0.5}) {
+		t.Errorf("Expected %#v, got %#v", neural.Huber{Delta: 0.5}, imported.Loss)
+	}
+}
+
+func TestDefaultLoss(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 2, 1}, neural.WithSeed(4))
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	explicit := cloneNetwork(t, n)
+	explicit.Loss = neural.MeanSquaredError{}
+
+	grads, err := n.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	expected, err := explicit.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	if grads.Loss != expected.Loss {
+		t.Errorf("Expected the mean squared error %v for a sigmoid output, got %v", expected.Loss, grads.Loss)
+	}
+
+	// The default is not written into the exported model
+	if imported := cloneNetwork(t, n); imported.Loss != nil {
+		t.Errorf("Expected no loss after export, got %#v", imported.Loss)
+	}
+}
+
+func mustExport(t *testing.T, nn *neuraln.NeuralNetwork) []byte {
+	t.Helper()
+	data, err := nn.ExportJSON()
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,220 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"math"
+	"neuraln/errors"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+var (
+	normalizationInputs  = [][]float64{{0.5, -1, 2}, {1, 0, -0.5}, {-1.5, 2, 0}, {0, 1, 1}, {2, -0.5, 0.5}}
+	normalizationTargets = [][]float64{{1, 0}, {0, 1}, {0.5, 0.5}, {1, 1}, {0, 0}}
+)
+
+func newNormalizedNetwork(t *testing.T, normalization neural.Normalization) *neural.Neural {
+	t.Helper()
+	n, err := (&neural.Neural{}).CreateDeep([]int{3, 4, 2},
+		neural.WithSeed(5),
+		neural.WithHiddenActivation(neural.Tanh{}),
+		neural.WithOutputActivation(neural.Linear{}),
+		neural.WithNormalization(0, normalization),
+	)
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	// Move Gamma and Beta away from their initial values so their gradients matter
+	n.Layers[0].Gamma.Matrix[1][0] = 1.5
+	n.Layers[0].Beta.Matrix[2][0] = -0.3
+	return n
+}
+
+func cloneNetwork(t *testing.T, n *neural.Neural) *neural.Neural {
+	t.Helper()
+	data, err := n.ExportJSON()
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	clone, err := neural.ImportJSON(data)
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	return clone
+}
+
+// trainingLoss returns the loss of the network in training mode, i.e. with the
+// statistics of the full batch. BatchNorm networks run one epoch with a zero
+// learning rate and a negligible momentum first, so their running statistics
+// equal the batch statistics.
+func trainingLoss(t *testing.T, n *neural.Neural) float64 {
+	t.Helper()
+	n = cloneNetwork(t, n)
+	n.LearningRate = 0
+	options := neural.TrainOptions{Epochs: 1, BatchSize: len(normalizationInputs)}
+	if _, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+	loss, err := n.Evaluate(normalizationInputs, normalizationTargets)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	return loss
+}
+
+func TestNormalizationGradients(t *testing.T) {
+	for _, normalization := range []neural.Normalization{neural.BatchNorm{Momentum: 1e-15}, neural.LayerNorm{}} {
+		t.Run(normalization.Name(), func(t *testing.T) {
+			n := newNormalizedNetwork(t, normalization)
+
+			// One full batch step of plain gradient descent with a learning rate of 1
+			// moves every parameter by minus its gradient
+			trained := cloneNetwork(t, n)
+			options := neural.TrainOptions{Epochs: 1, BatchSize: len(normalizationInputs)}
+			if _, err := trained.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+				t.Fatalf("TrainWithOptions failed: %v", err)
+			}
+
+			parameters := map[string]func(n *neural.Neural) *matrix.Matrix{
+				"weights": func(n *neural.Neural) *matrix.Matrix { return n.Layers[0].Weights },
+				"gamma":   func(n *neural.Neural) *matrix.Matrix { return n.Layers[0].Gamma },
+				"beta":    func(n *neural.Neural) *matrix.Matrix { return n.Layers[0].Beta },
+				"output":  func(n *neural.Neural) *matrix.Matrix { return n.Layers[1].Weights },
+			}
+			const h = 1e-6
+			for name, parameter := range parameters {
+				m := parameter(n)
+				for i := range m.Matrix {
+					for j := range m.Matrix[i] {
+						original := m.Matrix[i][j]
+						m.Matrix[i][j] = original + h
+						plus := trainingLoss(t, n)
+						m.Matrix[i][j] = original - h
+						minus := trainingLoss(t, n)
+						m.Matrix[i][j] = original
+
+						numerical := (plus - minus) / (2 * h)
+						analytical := original - parameter(trained).Matrix[i][j]
+						if math.Abs(numerical-analytical) > 1e-6 {
+							t.Errorf("%s[%d][%d]: expected gradient %v, got %v", name, i, j, numerical, analytical)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBatchNormRunningStatistics(t *testing.T) {
+	n := newNormalizedNetwork(t, neural.BatchNorm{Momentum: 0.5})
+	n.LearningRate = 0
+
+	options := neural.TrainOptions{Epochs: 1, BatchSize: len(normalizationInputs)}
+	if _, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	layer := n.Layers[0]
+	for i := range layer.Weights.Matrix {
+		sums := make([]float64, len(normalizationInputs))
+		mean := 0.0
+		for s, input := range normalizationInputs {
+			sums[s] = layer.Bias.Matrix[i][0]
+			for j, x := range input {
+				sums[s] += layer.Weights.Matrix[i][j] * x
+			}
+			mean += sums[s] / float64(len(sums))
+		}
+		variance := 0.0
+		for _, sum := range sums {
+			variance += (sum - mean) * (sum - mean) / float64(len(sums))
+		}
+
+		// Half of the initial statistics, zero mean and unit variance, are kept
+		if math.Abs(layer.RunningMean.Matrix[i][0]-mean/2) > 1e-12 {
+			t.Errorf("Node %d: expected running mean %v, got %v", i, mean/2, layer.RunningMean.Matrix[i][0])
+		}
+		if math.Abs(layer.RunningVariance.Matrix[i][0]-(0.5+variance/2)) > 1e-12 {
+			t.Errorf("Node %d: expected running variance %v, got %v", i, 0.5+variance/2, layer.RunningVariance.Matrix[i][0])
+		}
+	}
+}
+
+func TestGradientsKeepRunningStatistics(t *testing.T) {
+	n := newNormalizedNetwork(t, neural.BatchNorm{Momentum: 0.5})
+	n.LearningRate = 0
+	trained := cloneNetwork(t, n)
+	options := neural.TrainOptions{Epochs: 1, BatchSize: len(normalizationInputs)}
+	if _, err := trained.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	grads, err := n.Gradients(normalizationInputs, normalizationTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	assertClose(t, "running mean", matrix.New(4, 1), n.Layers[0].RunningMean)
+
+	// The update moves them like a training step on the same batch
+	if err := n.ApplyGradients(grads, nil); err != nil {
+		t.Fatalf("ApplyGradients failed: %v", err)
+	}
+	assertClose(t, "running mean", trained.Layers[0].RunningMean, n.Layers[0].RunningMean)
+	assertClose(t, "running variance", trained.Layers[0].RunningVariance, n.Layers[0].RunningVariance)
+}
+
+func TestBatchNormBatchSize(t *testing.T) {
+	n := newNormalizedNetwork(t, neural.BatchNorm{})
+	_, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, neural.TrainOptions{Epochs: 1})
+	if !goerrors.Is(err, errors.ErrBatchNormBatchSize) {
+		t.Errorf("Expected ErrBatchNormBatchSize for batches of one sample, got %v", err)
+	}
+	if _, err := n.Gradients(normalizationInputs[:1], normalizationTargets[:1]); !goerrors.Is(err, errors.ErrBatchNormBatchSize) {
+		t.Errorf("Expected ErrBatchNormBatchSize from Gradients for one sample, got %v", err)
+	}
+
+	// Five samples in batches of two leave one sample, which joins the second batch
+	for _, accumulation := range []int{1, 2} {
+		var steps []int
+		options := neural.TrainOptions{Epochs: 1, BatchSize: 2, AccumulationSteps: accumulation, Callbacks: []neural.Callback{{OnBatchEnd: func(log neural.BatchLog) error {
+			steps = append(steps, log.Step)
+			return nil
+		}}}}
+		if _, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+		if expected := 3 - accumulation; len(steps) != expected {
+			t.Errorf("AccumulationSteps %d: expected %d steps, got %d", accumulation, expected, len(steps))
+		}
+	}
+}
+
+func TestNormalizationPersisted(t *testing.T) {
+	for _, normalization := range []neural.Normalization{neural.BatchNorm{Momentum: 0.8}, neural.LayerNorm{Epsilon: 1e-3}} {
+		t.Run(normalization.Name(), func(t *testing.T) {
+			n := newNormalizedNetwork(t, normalization)
+			options := neural.TrainOptions{Epochs: 3, BatchSize: 2}
+			if _, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, options); err != nil {
+				t.Fatalf("TrainWithOptions failed: %v", err)
+			}
+
+			imported := cloneNetwork(t, n)
+			if imported.Layers[0].Normalization != normalization {
+				t.Errorf("Expected normalization %#v, got %#v", normalization, imported.Layers[0].Normalization)
+			}
+			if imported.Layers[1].Normalization != nil {
+				t.Errorf("Expected the output layer to stay unnormalized")
+			}
+			for _, input := range normalizationInputs {
+				expected, _ := n.FeedForword(input)
+				actual, _ := imported.FeedForword(input)
+				for i := range expected.Matrix {
+					if expected.Matrix[i][0] != actual.Matrix[i][0] {
+						t.Errorf("Expected the imported network to predict %v, got %v", expected.Matrix[i][0], actual.Matrix[i][0])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestOptimizersMinimizeQuadratic(t *testing.T) {
+	tests := []struct {
+		optimizer    neural.Optimizer
+		learningRate float64
+	}{
+		{&neural.SGD{}, 0.1},
+		{&neural.SGD{Momentum: 0.9}, 0.01},
+		{&neural.SGD{Momentum: 0.9, Nesterov: true}, 0.01},
+		{&neural.RMSProp{}, 0.01},
+		{&neural.Adagrad{}, 0.5},
+		{&neural.Adam{}, 0.05},
+		{&neural.AdamW{WeightDecay: 1e-4}, 0.05},
+	}
+
+	for _, test := range tests {
+		// Minimize (w - 3)^2 starting from w = 0
+		w := matrix.New(1, 1)
+		for step := 0; step < 500; step++ {
+			g := matrix.New(1, 1)
+			g.Matrix[0][0] = 2 * (w.Matrix[0][0] - 3)
+			err := test.optimizer.Step([]neural.Parameter{{Name: "w", Value: w, Gradient: g}}, test.learningRate)
+			if err != nil {
+				t.Fatalf("%s: Step failed: %v", test.optimizer.Name(), err)
+			}
+		}
+
+		if math.Abs(w.Matrix[0][0]-3) > 0.05 {
+			t.Errorf("%s: expected w to approach 3, got %v", test.optimizer.Name(), w.Matrix[0][0])
+		}
+	}
+}
+
+func TestSGDMomentum(t *testing.T) {
+	w := matrix.New(1, 1)
+	g := matrix.New(1, 1)
+	g.Matrix[0][0] = 1
+
+	sgd := &neural.SGD{Momentum: 0.5}
+	expected := []float64{-0.1, -0.25, -0.425}
+	for _, want := range expected {
+		if err := sgd.Step([]neural.Parameter{{Name: "w", Value: w, Gradient: g}}, 0.1); err != nil {
+			t.Fatalf("Step failed: %v", err)
+		}
+		if math.Abs(w.Matrix[0][0]-want) > 1e-12 {
+			t.Errorf("Expected %v, got %v", want, w.Matrix[0][0])
+		}
+	}
+}
+
+func TestOptimizerStateSurvivesExport(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{2, 4, 1},
+		neural.WithOptimizer(&neural.Adam{}),
+		neural.WithLearningRate(0.01),
+	)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	// A single sample keeps the order of the training steps deterministic
+	inputs := [][]float64{{1, 0}}
+	targets := [][]float64{{1}}
+
+	if err := nn.Train(inputs, targets, 5); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	resumed, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	adam, ok := resumed.Optimizer.(*neural.Adam)
+	if !ok || adam.Steps != 5 || len(adam.M) != 4 || len(adam.V) != 4 {
+		t.Fatalf("Expected the Adam state of 5 steps to be restored, got %#v", resumed.Optimizer)
+	}
+
+	if err := nn.Train(inputs, targets, 5); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+	if err := resumed.Train(inputs, targets, 5); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	want, _ := nn.Predict(inputs[0])
+	got, _ := resumed.FeedForword(inputs[0])
+	if got.Flatten()[0] != want[0] {
+		t.Errorf("Expected the resumed run to match the original one, got %v and %v", got.Flatten(), want)
+	}
+}
+
+func TestDefaultOptimizerNotExported(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 4, 1})
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	if err := n.Train(xorInputs, xorTargets, 2); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	// The default optimizer is stored as null, like the default loss
+	if imported := cloneNetwork(t, n); imported.Optimizer != nil {
+		t.Errorf("Expected no optimizer after export, got %#v", imported.Optimizer)
+	}
+}
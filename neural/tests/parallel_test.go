@@ -0,0 +1,54 @@
+package neural_test
+
+import (
+	"bytes"
+	"math"
+	"neuraln/neural"
+	"testing"
+)
+
+func trainParallel(t *testing.T, workers int, options ...neural.Option) *neural.Neural {
+	t.Helper()
+	options = append([]neural.Option{neural.WithSeed(12), neural.WithHiddenActivation(neural.Tanh{})}, options...)
+	n, err := (&neural.Neural{}).CreateDeep([]int{3, 16, 8, 2}, options...)
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	trainOptions := neural.TrainOptions{Epochs: 20, BatchSize: 5, Workers: workers}
+	if _, err := n.TrainWithOptions(normalizationInputs, normalizationTargets, trainOptions); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+	return n
+}
+
+func TestParallelMatchesSingleGoroutine(t *testing.T) {
+	for _, workers := range []int{2, 3, 8} {
+		single := trainParallel(t, 1, neural.WithHiddenDropout(0.3), neural.WithNormalization(1, neural.LayerNorm{}))
+		parallel := trainParallel(t, workers, neural.WithHiddenDropout(0.3), neural.WithNormalization(1, neural.LayerNorm{}))
+
+		for i := range single.Layers {
+			expected, actual := single.Layers[i].Weights.Flatten(), parallel.Layers[i].Weights.Flatten()
+			for j := range expected {
+				if math.Abs(expected[j]-actual[j]) > 1e-9 {
+					t.Fatalf("%d workers, layer %d: expected weight %v, got %v", workers, i, expected[j], actual[j])
+				}
+			}
+		}
+	}
+}
+
+func TestParallelIsDeterministic(t *testing.T) {
+	first, _ := trainParallel(t, 3, neural.WithHiddenDropout(0.3)).ExportJSON()
+	second, _ := trainParallel(t, 3, neural.WithHiddenDropout(0.3)).ExportJSON()
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected parallel training to be reproducible")
+	}
+}
+
+func TestParallelBatchNorm(t *testing.T) {
+	single, _ := trainParallel(t, 1, neural.WithHiddenNormalization(neural.BatchNorm{})).ExportJSON()
+	parallel, _ := trainParallel(t, 4, neural.WithHiddenNormalization(neural.BatchNorm{})).ExportJSON()
+	if !bytes.Equal(single, parallel) {
+		t.Errorf("Expected BatchNorm networks to train on a single goroutine")
+	}
+}
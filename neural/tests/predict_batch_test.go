@@ -0,0 +1,61 @@
+package neural_test
+
+import (
+	goerrors "errors"
+	"math/rand/v2"
+	"neuraln"
+	"neuraln/errors"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestPredictBatch(t *testing.T) {
+	nn, err := neuraln.NewDeep([]int{3, 8, 2}, neural.WithHiddenNormalization(neural.LayerNorm{}))
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+
+	// Spans several chunks, the last one partial
+	r := rand.New(rand.NewPCG(1, 1))
+	inputs := make([][]float64, 2500)
+	for i := range inputs {
+		inputs[i] = []float64{r.Float64(), r.Float64(), r.Float64()}
+	}
+
+	outputs, err := nn.PredictBatch(inputs)
+	if err != nil {
+		t.Fatalf("PredictBatch failed: %v", err)
+	}
+	if len(outputs) != len(inputs) {
+		t.Fatalf("Expected %d outputs, got %d", len(inputs), len(outputs))
+	}
+	for i, input := range inputs {
+		expected, _ := nn.Predict(input)
+		for j := range expected {
+			if outputs[i][j] != expected[j] {
+				t.Fatalf("Row %d: expected %v, got %v", i, expected, outputs[i])
+			}
+		}
+	}
+}
+
+func TestPredictBatchRowError(t *testing.T) {
+	nn := neuraln.New(2, 4, 1)
+
+	_, err := nn.PredictBatch([][]float64{{0, 1}, {1, 0}, {1}, {0, 0, 0}})
+	var rowErr *errors.RowError
+	if !goerrors.As(err, &rowErr) || rowErr.Row != 2 {
+		t.Fatalf("Expected an error for row 2, got %v", err)
+	}
+	if !goerrors.Is(err, errors.ErrInputNodesMismatch) {
+		t.Errorf("Expected %v, got %v", errors.ErrInputNodesMismatch, err)
+	}
+	if err.Error() != "row 2: "+errors.ErrInputNodesMismatch.Error() {
+		t.Errorf("Expected the message to name the row, got %q", err.Error())
+	}
+
+	outputs, err := nn.PredictBatch(nil)
+	if err != nil || len(outputs) != 0 {
+		t.Errorf("Expected no outputs for no inputs, got %v, %v", outputs, err)
+	}
+}
@@ -0,0 +1,123 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+)
+
+// newConstantNetwork creates a linear 2-3-1 network whose weights are all 0.5 and
+// whose biases are all zero, so zero inputs produce zero outputs.
+func newConstantNetwork(t *testing.T, options ...neural.Option) *neuraln.NeuralNetwork {
+	t.Helper()
+	options = append([]neural.Option{
+		neural.WithHiddenActivation(neural.Linear{}),
+		neural.WithOutputActivation(neural.Linear{}),
+		neural.WithInitializers(neural.Constant{Value: 0.5}, neural.Zeros{}),
+		neural.WithLearningRate(0.1),
+	}, options...)
+
+	nn, err := neuraln.NewDeep([]int{2, 3, 1}, options...)
+	if err != nil {
+		t.Fatalf("NewDeep failed: %v", err)
+	}
+	return nn
+}
+
+func TestRegularizationPenalty(t *testing.T) {
+	inputs := [][]float64{{1, 2}}
+	targets := [][]float64{{0}}
+
+	plain, err := newConstantNetwork(t).Evaluate(inputs, targets)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	penalized, err := newConstantNetwork(t, neural.WithGlobalRegularization(0.01, 0.1)).Evaluate(inputs, targets)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	// 9 weights of 0.5: 0.01 * 9 * 0.5 + 0.1 * 9 * 0.25
+	if expected := 0.045 + 0.225; math.Abs(penalized-plain-expected) > 1e-12 {
+		t.Errorf("Expected a penalty of %v, got %v", expected, penalized-plain)
+	}
+}
+
+func TestRegularizationGradient(t *testing.T) {
+	nn := newConstantNetwork(t, neural.WithRegularization(0, 0.01, 0.1), neural.WithRegularization(1, 0.02, 0))
+	inputs := [][]float64{{0, 0}}
+	targets := [][]float64{{0}}
+
+	// The data loss is zero, so only the penalties move the weights
+	if err := nn.Train(inputs, targets, 1); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	n, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	expected := []float64{0.5 - 0.1*(0.01+2*0.1*0.5), 0.5 - 0.1*0.02}
+	for i, layer := range n.Layers {
+		for _, w := range layer.Weights.Flatten() {
+			if math.Abs(w-expected[i]) > 1e-12 {
+				t.Errorf("Layer %d: expected weight %v, got %v", i, expected[i], w)
+			}
+		}
+		for _, b := range layer.Bias.Flatten() {
+			if b != 0 {
+				t.Errorf("Layer %d: expected the bias to be left alone, got %v", i, b)
+			}
+		}
+	}
+}
+
+func TestRegularizeBias(t *testing.T) {
+	create := func(regularizeBias bool) *neural.Neural {
+		n, err := (&neural.Neural{}).CreateDeep([]int{2, 3, 1},
+			neural.WithInitializers(neural.Zeros{}, neural.Constant{Value: 2}),
+			neural.WithGlobalRegularization(0, 0.1),
+		)
+		if err != nil {
+			t.Fatalf("CreateDeep failed: %v", err)
+		}
+		for _, layer := range n.Layers {
+			layer.RegularizeBias = regularizeBias
+		}
+		return n
+	}
+
+	inputs := [][]float64{{1, 2}}
+	targets := [][]float64{{0}}
+
+	excluded, _ := create(false).Evaluate(inputs, targets)
+	included, _ := create(true).Evaluate(inputs, targets)
+
+	// 4 biases of 2: 0.1 * 4 * 4
+	if math.Abs(included-excluded-1.6) > 1e-12 {
+		t.Errorf("Expected the biases to add a penalty of 1.6, got %v", included-excluded)
+	}
+}
+
+func TestWeightDecay(t *testing.T) {
+	nn := newConstantNetwork(t, neural.WithWeightDecay(0.5), neural.WithOptimizer(&neural.Adam{}))
+
+	// Zero gradients leave Adam idle, so only the decay changes the weights
+	if err := nn.Train([][]float64{{0, 0}}, [][]float64{{0}}, 1); err != nil {
+		t.Fatalf("Train failed: %v", err)
+	}
+
+	n, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	for i, layer := range n.Layers {
+		for _, w := range layer.Weights.Flatten() {
+			if math.Abs(w-0.5*0.95) > 1e-12 {
+				t.Errorf("Layer %d: expected weight %v, got %v", i, 0.5*0.95, w)
+			}
+		}
+	}
+}
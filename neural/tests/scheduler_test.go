@@ -0,0 +1,87 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestSchedulers(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheduler neural.Scheduler
+		epoch     int
+		step      int
+		expected  float64
+	}{
+		{"step decay before the first step", neural.StepDecay{StepSize: 10, Gamma: 0.5}, 9, 0, 1},
+		{"step decay after two steps", neural.StepDecay{StepSize: 10, Gamma: 0.5}, 25, 0, 0.25},
+		{"exponential decay", neural.ExponentialDecay{Gamma: 0.9}, 2, 0, 0.81},
+		{"cosine start", neural.CosineAnnealing{Period: 10, MinRate: 0.1}, 0, 0, 1},
+		{"cosine middle", neural.CosineAnnealing{Period: 10, MinRate: 0.1}, 5, 0, 0.55},
+		{"cosine restart", neural.CosineAnnealing{Period: 10, MinRate: 0.1}, 10, 0, 1},
+		{"cosine doubled period", neural.CosineAnnealing{Period: 10, Mult: 2, MinRate: 0.1}, 20, 0, 0.55},
+		{"one cycle start", neural.OneCycle{MaxRate: 2.5, TotalSteps: 100}, 0, 0, 0.1},
+		{"one cycle peak", neural.OneCycle{MaxRate: 2.5, TotalSteps: 100}, 0, 30, 2.5},
+		{"one cycle end", neural.OneCycle{MaxRate: 2.5, TotalSteps: 100}, 0, 100, 1e-5},
+		{"one cycle warmup only", neural.OneCycle{MaxRate: 2.5, TotalSteps: 100, PctStart: 1}, 0, 50, 1.3},
+		{"one cycle after warmup only", neural.OneCycle{MaxRate: 2.5, TotalSteps: 100, PctStart: 1}, 0, 100, 1e-5},
+	}
+
+	for _, test := range tests {
+		rate := test.scheduler.LearningRate(1, test.epoch, test.step)
+		if math.Abs(rate-test.expected) > 1e-12 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, rate)
+		}
+	}
+}
+
+func TestReduceOnPlateau(t *testing.T) {
+	scheduler := &neural.ReduceOnPlateau{Factor: 0.5, Patience: 1, MinRate: 0.2}
+
+	losses := []float64{1, 0.9, 0.95, 0.95, 0.8, 0.9, 0.9, 0.9, 0.9}
+	expected := []float64{1, 1, 1, 0.5, 0.5, 0.5, 0.25, 0.25, 0.2}
+
+	for i, loss := range losses {
+		scheduler.Observe(loss)
+		if rate := scheduler.LearningRate(1, i, 0); math.Abs(rate-expected[i]) > 1e-12 {
+			t.Errorf("After observing %v: expected %v, got %v", losses[:i+1], expected[i], rate)
+		}
+	}
+}
+
+// recordingScheduler remembers the epoch and step of every call.
+type recordingScheduler struct {
+	epochs []int
+	steps  []int
+}
+
+func (s *recordingScheduler) LearningRate(base float64, epoch, step int) float64 {
+	s.epochs = append(s.epochs, epoch)
+	s.steps = append(s.steps, step)
+	return base
+}
+
+func TestTrainConsultsScheduler(t *testing.T) {
+	nn := neuraln.New(2, 2, 1)
+
+	inputs := [][]float64{{1, 0}, {0, 1}, {1, 1}, {0, 0}, {1, 0}}
+	targets := [][]float64{{1}, {1}, {0}, {0}, {1}}
+
+	scheduler := &recordingScheduler{}
+	options := neural.TrainOptions{Epochs: 2, BatchSize: 2, Scheduler: scheduler}
+	if _, err := nn.TrainWithOptions(inputs, targets, options); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+
+	expectedEpochs := []int{0, 0, 0, 1, 1, 1}
+	if len(scheduler.epochs) != len(expectedEpochs) {
+		t.Fatalf("Expected %d steps, got %d", len(expectedEpochs), len(scheduler.epochs))
+	}
+	for i := range expectedEpochs {
+		if scheduler.epochs[i] != expectedEpochs[i] || scheduler.steps[i] != i {
+			t.Errorf("Call %d: expected epoch %d step %d, got epoch %d step %d", i, expectedEpochs[i], i, scheduler.epochs[i], scheduler.steps[i])
+		}
+	}
+}
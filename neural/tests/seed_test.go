@@ -0,0 +1,75 @@
+package neural_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"neuraln"
+	"neuraln/neural"
+	"testing"
+)
+
+func TestSeedIsReproducible(t *testing.T) {
+	train := func(seed uint64) []byte {
+		nn, err := neuraln.NewDeep([]int{2, 16, 8, 1}, neural.WithSeed(seed), neural.WithOptimizer(&neural.Adam{}))
+		if err != nil {
+			t.Fatalf("NewDeep failed: %v", err)
+		}
+		if _, err := nn.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 20, BatchSize: 3}); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+		return mustExport(t, nn)
+	}
+
+	first, second := train(42), train(42)
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected identical seeds to produce identical networks")
+	}
+	if bytes.Equal(first, train(43)) {
+		t.Errorf("Expected different seeds to produce different networks")
+	}
+}
+
+func TestTrainSource(t *testing.T) {
+	nn := neuraln.New(2, 8, 1)
+	first, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	second, err := neuraln.ImportJSON(mustExport(t, nn))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+
+	for _, n := range []*neural.Neural{first, second} {
+		options := neural.TrainOptions{Epochs: 10, Source: rand.NewPCG(7, 7)}
+		if _, err := n.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+			t.Fatalf("TrainWithOptions failed: %v", err)
+		}
+	}
+
+	a, _ := first.ExportJSON()
+	b, _ := second.ExportJSON()
+	if !bytes.Equal(a, b) {
+		t.Errorf("Expected identical training sources to produce identical networks")
+	}
+}
+
+func TestSourceReproducesGradients(t *testing.T) {
+	gradients := func() *neural.Gradients {
+		nn, err := neuraln.NewDeep([]int{2, 8, 1}, neural.WithSource(rand.NewPCG(5, 5)), neural.WithHiddenDropout(0.5))
+		if err != nil {
+			t.Fatalf("NewDeep failed: %v", err)
+		}
+		grads, err := nn.Gradients(xorInputs, xorTargets)
+		if err != nil {
+			t.Fatalf("Gradients failed: %v", err)
+		}
+		return grads
+	}
+
+	// The dropout masks of Gradients come from the source as well
+	first, second := gradients(), gradients()
+	for i := range first.Layers {
+		assertClose(t, "weights", first.Layers[i].Weights, second.Layers[i].Weights)
+	}
+}
@@ -0,0 +1,82 @@
+package neural_test
+
+import (
+	"math"
+	"neuraln/matrix"
+	"neuraln/neural"
+	"testing"
+)
+
+// transposedView returns a 2x2 matrix with the given values, stored as the
+// transpose of a dense matrix, so it has no contiguous rows.
+func transposedView(a, b, c, d float64) *matrix.Matrix {
+	return matrix.NewFromData(2, 2, []float64{a, c, b, d}).T()
+}
+
+func TestApplyGradientsWithViews(t *testing.T) {
+	optimizers := []func() neural.Optimizer{
+		func() neural.Optimizer { return &neural.SGD{} },
+		func() neural.Optimizer { return &neural.SGD{Momentum: 0.9} },
+		func() neural.Optimizer { return &neural.RMSProp{} },
+		func() neural.Optimizer { return &neural.Adagrad{} },
+		func() neural.Optimizer { return &neural.Adam{} },
+		func() neural.Optimizer { return &neural.AdamW{} },
+	}
+	for _, optimizer := range optimizers {
+		n, err := (&neural.Neural{}).CreateDeep([]int{2, 2, 2}, neural.WithSeed(3))
+		if err != nil {
+			t.Fatalf("CreateDeep failed: %v", err)
+		}
+		grads, err := n.Gradients(xorInputs, [][]float64{{0, 1}, {1, 0}, {1, 0}, {0, 1}})
+		if err != nil {
+			t.Fatalf("Gradients failed: %v", err)
+		}
+
+		// The same update once through a dense gradient and once through a view
+		dense := cloneNetwork(t, n)
+		grads.Layers[0].Weights = matrix.NewFromData(2, 2, []float64{0.5, -1, 2, 0.25})
+		if err := dense.ApplyGradients(grads, optimizer()); err != nil {
+			t.Fatalf("ApplyGradients failed: %v", err)
+		}
+		grads.Layers[0].Weights = transposedView(0.5, -1, 2, 0.25)
+		if err := n.ApplyGradients(grads, optimizer()); err != nil {
+			t.Fatalf("ApplyGradients with a view failed: %v", err)
+		}
+		assertClose(t, optimizer().Name(), dense.Layers[0].Weights, n.Layers[0].Weights)
+	}
+}
+
+func TestGradientsAddViews(t *testing.T) {
+	view := func() *neural.Gradients {
+		return &neural.Gradients{Layers: []neural.LayerGradients{{Weights: transposedView(1, 2, 3, 4), Bias: matrix.New(2, 1)}}}
+	}
+
+	g := &neural.Gradients{Layers: []neural.LayerGradients{{Weights: matrix.New(2, 2), Bias: matrix.New(2, 1)}}}
+	if err := g.Add(view()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	assertClose(t, "weights", matrix.NewFromData(2, 2, []float64{1, 2, 3, 4}), g.Layers[0].Weights)
+
+	// Adding to a view writes through to its storage
+	g = view()
+	if err := g.Add(view()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if g.Layers[0].Weights.At(1, 0) != 6 {
+		t.Errorf("Expected 6 at (1, 0), got %v", g.Layers[0].Weights.At(1, 0))
+	}
+}
+
+func TestSoftmaxAndLossOnViews(t *testing.T) {
+	z := transposedView(1, 2, 3, 4)
+	result := neural.Softmax{}.Forward(z)
+	expected := 1 / (1 + math.Exp(-2))
+	if math.Abs(result.At(1, 0)-expected) > 1e-12 {
+		t.Errorf("Expected %v, got %v", expected, result.At(1, 0))
+	}
+
+	loss, err := neural.MeanSquaredError{}.Loss(z, matrix.NewFromData(2, 2, []float64{1, 2, 3, 4}))
+	if err != nil || loss != 0 {
+		t.Errorf("Expected a loss of 0 for a view equal to its targets, got %v, %v", loss, err)
+	}
+}
@@ -0,0 +1,62 @@
+package neural_test
+
+import (
+	"neuraln/neural"
+	"testing"
+)
+
+// TestTrainReusesBuffers checks that the buffers Train reuses across steps do not
+// leak values from one step into the next: several full-batch epochs, with one or
+// more workers and micro-batches, must match as many explicit updates.
+func TestTrainReusesBuffers(t *testing.T) {
+	cases := map[string]neural.TrainOptions{
+		"single":       {BatchSize: len(xorInputs)},
+		"workers":      {BatchSize: len(xorInputs), Workers: 2},
+		"accumulation": {BatchSize: 2, AccumulationSteps: 2},
+	}
+	for name, options := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 4, 1}, neural.WithSeed(8), neural.WithLearningRate(0.5), neural.WithHiddenActivation(neural.Tanh{}))
+			if err != nil {
+				t.Fatalf("CreateDeep failed: %v", err)
+			}
+			trained := cloneNetwork(t, n)
+			options.Epochs = 5
+			if _, err := trained.TrainWithOptions(xorInputs, xorTargets, options); err != nil {
+				t.Fatalf("TrainWithOptions failed: %v", err)
+			}
+
+			for epoch := 0; epoch < options.Epochs; epoch++ {
+				grads, err := n.Gradients(xorInputs, xorTargets)
+				if err != nil {
+					t.Fatalf("Gradients failed: %v", err)
+				}
+				if err := n.ApplyGradients(grads, nil); err != nil {
+					t.Fatalf("ApplyGradients failed: %v", err)
+				}
+			}
+
+			for i, layer := range n.Layers {
+				assertClose(t, "weights", layer.Weights, trained.Layers[i].Weights)
+				assertClose(t, "bias", layer.Bias, trained.Layers[i].Bias)
+			}
+		})
+	}
+}
+
+func TestGradientsOutliveTraining(t *testing.T) {
+	n, err := (&neural.Neural{}).CreateDeep([]int{2, 8, 1}, neural.WithSeed(9))
+	if err != nil {
+		t.Fatalf("CreateDeep failed: %v", err)
+	}
+	grads, err := n.Gradients(xorInputs, xorTargets)
+	if err != nil {
+		t.Fatalf("Gradients failed: %v", err)
+	}
+	before := grads.Layers[0].Weights.Copy()
+
+	if _, err := n.TrainWithOptions(xorInputs, xorTargets, neural.TrainOptions{Epochs: 3, BatchSize: len(xorInputs)}); err != nil {
+		t.Fatalf("TrainWithOptions failed: %v", err)
+	}
+	assertClose(t, "weights", before, grads.Layers[0].Weights)
+}
@@ -1,12 +1,60 @@
 package neural
 
 import (
+	"context"
+	goerrors "errors"
 	"math/rand/v2"
 	"neuraln/errors"
 	"neuraln/matrix"
 )
 
+// TrainOptions configures TrainWithOptions.
+type TrainOptions struct {
+	// Epochs is the number of passes over the training data.
+	Epochs int
+	// BatchSize is the number of samples packed into one matrix per training step.
+	// The gradients are averaged over the batch. Values below one mean one sample
+	// per step.
+	BatchSize int
+	// AccumulationSteps is the number of batches of BatchSize samples whose gradients
+	// are accumulated before every optimizer step. The accumulated gradients are
+	// weighted by the size of every batch, so a step matches a single batch of
+	// AccumulationSteps * BatchSize samples while only BatchSize samples are packed
+	// into a matrix at a time. Values below one mean one batch per step.
+	AccumulationSteps int
+	// Workers splits every batch into up to Workers shards whose gradients are
+	// computed in parallel and averaged before the update. The results match a
+	// single goroutine within floating-point tolerance, also with dropout. Networks
+	// with BatchNorm layers always train on one goroutine. Values below two train
+	// on the calling goroutine.
+	Workers int
+	// Scheduler adjusts the learning rate of every step. When nil, the network's
+	// LearningRate is used throughout.
+	Scheduler Scheduler
+	// Metrics are measured on every batch and on the validation data in addition
+	// to the loss.
+	Metrics []Metric
+	// ValidationInputs and ValidationTargets are evaluated at the end of every epoch.
+	ValidationInputs  [][]float64
+	ValidationTargets [][]float64
+	// ValidationSplit holds out this fraction of the samples, taken from the end of
+	// the input and target arrays, as validation data. It is ignored when
+	// ValidationInputs are given.
+	ValidationSplit float64
+	// EarlyStopping ends training once the monitored value stops improving.
+	EarlyStopping *EarlyStopping
+	// Clipping limits the gradients of every step before the optimizer applies them.
+	Clipping *Clipping
+	// Source, when set, drives the shuffling of this training run instead of the
+	// network's random number generator. The dropout masks are always drawn from
+	// the network's generator.
+	Source rand.Source
+	// Callbacks are notified after every step and every epoch.
+	Callbacks []Callback
+}
+
 // Train trains the neural network using the provided input and target arrays for a specified number of epochs.
+// Every sample is a training step of its own, see TrainWithOptions for mini-batches.
 //
 // Parameters:
 //   - inputArray: A slice of float64 representing the input data.
@@ -16,33 +64,322 @@ import (
 // Returns:
 //   - error: An error if the input and target arrays do not match the expected dimensions, otherwise nil.
 func (neural *Neural) Train(inputArray, targetArray [][]float64, epochs int) error {
+	_, err := neural.TrainWithOptions(inputArray, targetArray, TrainOptions{Epochs: epochs})
+	return err
+}
+
+// TrainWithOptions trains the neural network on mini-batches of the provided input and
+// target arrays. Every epoch shuffles the samples, packs BatchSize of them as the columns
+// of one input and one target matrix and performs a training step per batch, or per
+// AccumulationSteps batches; the last batch of an epoch may be smaller, but networks with
+// BatchNorm layers merge a last batch of one sample into the one before. A Scheduler, if
+// given, sets the learning rate of every step.
+//
+// After every step and every epoch the callbacks are notified, and the loss and metrics
+// of every epoch, on the training and the validation data, are recorded in the returned
+// History. A callback returning errors.ErrStopTraining ends training early, and so
+// does EarlyStopping once the monitored value stops improving, optionally restoring
+// the weights of the best epoch.
+//
+// Parameters:
+//   - inputArray: A slice of float64 representing the input data.
+//   - targetArray: A slice of float64 representing the target data.
+//   - options: The number of epochs, the batch size, the learning rate schedule, the
+//     validation data, early stopping, gradient clipping and the callbacks.
+//
+// Returns:
+//   - *History: The completed epochs, also when a callback stopped training early.
+//   - error: An error if the input, target or validation arrays do not match the expected
+//     dimensions, the validation split or the clipping thresholds are out of range, a
+//     network with BatchNorm layers gets batches of one sample or a callback fails,
+//     otherwise nil.
+func (neural *Neural) TrainWithOptions(inputArray, targetArray [][]float64, options TrainOptions) (*History, error) {
+	return neural.TrainContext(context.Background(), inputArray, targetArray, options)
+}
+
+// TrainContext is TrainWithOptions with cancellation. The context is checked before
+// every training step; once it is done, training returns the completed epochs and
+// ctx.Err(). Every step updates the network as a whole, so a cancelled network holds
+// the weights of its last completed step and can still be used or exported.
+func (neural *Neural) TrainContext(ctx context.Context, inputArray, targetArray [][]float64, options TrainOptions) (*History, error) {
 	if err := neural.validate(inputArray, targetArray); err != nil {
-		return err
+		return nil, err
+	}
+	if options.Clipping != nil && (options.Clipping.Value < 0 || options.Clipping.Norm < 0) {
+		return nil, errors.ErrClipping
+	}
+	if options.ValidationSplit > 0 && len(options.ValidationInputs) == 0 {
+		held := int(float64(len(inputArray)) * options.ValidationSplit)
+		if held < 1 || held >= len(inputArray) {
+			return nil, errors.ErrValidationSplit
+		}
+		split := len(inputArray) - held
+		options.ValidationInputs, options.ValidationTargets = inputArray[split:], targetArray[split:]
+		inputArray, targetArray = inputArray[:split], targetArray[:split]
+	}
+	validated := len(options.ValidationInputs) > 0 || len(options.ValidationTargets) > 0
+	if validated {
+		if err := neural.validate(options.ValidationInputs, options.ValidationTargets); err != nil {
+			return nil, err
+		}
 	}
 
-	for i := 0; i < epochs; i++ {
+	batchSize := options.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	// BatchNorm normalizes a single sample to zero, so it never trains on one
+	normalized := neural.batchNormalized()
+	if normalized && (batchSize < 2 || len(inputArray) < 2) {
+		return nil, errors.ErrBatchNormBatchSize
+	}
+	stepSize := batchSize
+	if options.AccumulationSteps > 1 {
+		stepSize *= options.AccumulationSteps
+	}
+
+	var stopper *earlyStopper
+	if options.EarlyStopping != nil {
+		var err error
+		if stopper, err = newEarlyStopper(options.EarlyStopping, options.Metrics, validated); err != nil {
+			return nil, err
+		}
+	}
+
+	shuffler := neural.random()
+	if options.Source != nil {
+		shuffler = rand.New(options.Source)
+	}
+
+	history := &History{BestEpoch: -1}
+	ws := &workspace{}
+	step := 0
+	for epoch := 0; epoch < options.Epochs; epoch++ {
 		// Shuffle the input and target arrays
-		shuffledInputs, shuffledTargets := shuffleArrays(inputArray, targetArray)
+		shuffledInputs, shuffledTargets := shuffleArrays(shuffler, inputArray, targetArray)
+
+		log := EpochLog{Epoch: epoch, Validated: validated}
+		totals := newTotals(options.Metrics)
+		stop := false
+		for batch, start, end := 0, 0, 0; start < len(shuffledInputs) && !stop; batch, start = batch+1, end {
+			end = start + stepSize
+			if end > len(shuffledInputs) || (normalized && end == len(shuffledInputs)-1) {
+				end = len(shuffledInputs)
+			}
+
+			if err := ctx.Err(); err != nil {
+				return history, err
+			}
+
+			learningRate := neural.LearningRate
+			if options.Scheduler != nil {
+				learningRate = options.Scheduler.LearningRate(neural.LearningRate, epoch, step)
+			}
 
-		for j := 0; j < len(shuffledInputs); j++ {
-			// Convert the shuffled input and target arrays to matrices
-			inputs := matrix.NewFromArray(shuffledInputs[j])
-			targets := matrix.NewFromArray(shuffledTargets[j])
+			// Accumulate the gradients of the batches of this step, weighted by their share
+			// of the samples
+			var grads *Gradients
+			for micro, microEnd := start, start; micro < end; micro = microEnd {
+				microEnd = micro + batchSize
+				if microEnd > end || (normalized && microEnd == end-1) {
+					microEnd = end
+				}
+
+				microGrads, outputs, err := neural.shardedGradients(shuffledInputs[micro:microEnd], shuffledTargets[micro:microEnd], neural.random(), options.Workers, ws)
+				if err != nil {
+					return history, err
+				}
+				targets := ws.columns(shuffledTargets[micro:microEnd], true)
+				if err := totals.add(outputs, targets, microGrads.Loss); err != nil {
+					return history, err
+				}
+
+				microGrads.Scale(float64(microEnd-micro) / float64(end-start))
+				switch {
+				case grads == nil && microEnd < end:
+					// The next micro-batch reuses the buffers of these gradients
+					grads = ws.accumulate(microGrads)
+				case grads == nil:
+					grads = microGrads
+				default:
+					if err := grads.Add(microGrads); err != nil {
+						return history, err
+					}
+				}
+			}
 
-			// Perform backpropagation
-			err := neural.backPropagate(inputs, targets)
+			// Perform the optimizer step
+			norm, err := neural.update(grads, learningRate, options.Clipping)
 			if err != nil {
-				return err
+				return history, err
+			}
+			log.LearningRate = learningRate
+
+			batchLog := BatchLog{Epoch: epoch, Batch: batch, Step: step, Loss: grads.Loss, LearningRate: learningRate, GradientNorm: norm}
+			step++
+			for _, callback := range options.Callbacks {
+				if callback.OnBatchEnd == nil {
+					continue
+				}
+				stopped, err := notify(callback.OnBatchEnd(batchLog))
+				if err != nil {
+					return history, err
+				}
+				stop = stop || stopped
+			}
+		}
+
+		log.Loss, log.Metrics = totals.average()
+		if validated {
+			var err error
+			log.ValidationLoss, log.ValidationMetrics, err = neural.evaluate(options.ValidationInputs, options.ValidationTargets, options.Metrics)
+			if err != nil {
+				return history, err
 			}
 		}
+
+		if plateau, ok := options.Scheduler.(PlateauScheduler); ok {
+			if validated {
+				plateau.Observe(log.ValidationLoss)
+			} else {
+				plateau.Observe(log.Loss)
+			}
+		}
+
+		history.Epochs = append(history.Epochs, log)
+		for _, callback := range options.Callbacks {
+			if callback.OnEpochEnd == nil {
+				continue
+			}
+			stopped, err := notify(callback.OnEpochEnd(log))
+			if err != nil {
+				return history, err
+			}
+			stop = stop || stopped
+		}
+
+		if stopper != nil {
+			stopped, err := stopper.observe(neural, log)
+			if err != nil {
+				return history, err
+			}
+			history.BestEpoch = stopper.epoch
+			stop = stop || stopped
+		}
+		if stop {
+			history.StoppedEarly = epoch < options.Epochs-1
+			break
+		}
+	}
+
+	if stopper != nil {
+		stopper.restore(neural)
 	}
 
+	return history, nil
+}
+
+// notify interprets the error returned by a callback: errors.ErrStopTraining, also
+// when wrapped, asks training to stop and any other error aborts it.
+func notify(err error) (bool, error) {
+	if goerrors.Is(err, errors.ErrStopTraining) {
+		return true, nil
+	}
+	return false, err
+}
+
+// totals accumulates the loss and metrics of several batches, weighted by the number
+// of samples in every batch.
+type totals struct {
+	metrics []Metric
+	loss    float64
+	values  []float64
+	samples int
+}
+
+func newTotals(metrics []Metric) *totals {
+	return &totals{metrics: metrics, values: make([]float64, len(metrics))}
+}
+
+// add records the loss of a batch and measures every metric on it.
+func (t *totals) add(outputs, targets *matrix.Matrix, loss float64) error {
+	weight := float64(outputs.Col)
+	t.loss += loss * weight
+	for i, metric := range t.metrics {
+		value, err := metric.Measure(outputs, targets)
+		if err != nil {
+			return err
+		}
+		t.values[i] += value * weight
+	}
+	t.samples += outputs.Col
 	return nil
 }
 
+// average returns the loss and the metrics averaged over every recorded sample.
+func (t *totals) average() (float64, map[string]float64) {
+	if t.samples == 0 {
+		return 0, nil
+	}
+
+	var metrics map[string]float64
+	if len(t.metrics) > 0 {
+		metrics = make(map[string]float64, len(t.metrics))
+		for i, metric := range t.metrics {
+			metrics[metric.Name()] = t.values[i] / float64(t.samples)
+		}
+	}
+	return t.loss / float64(t.samples), metrics
+}
+
+// evaluateBatchSize is the number of samples Evaluate runs through the network at once.
+const evaluateBatchSize = 256
+
+// Evaluate returns the network's loss averaged over the given samples, including the
+// L1 and L2 penalties of the layers, without changing the network.
+//
+// Returns:
+//   - float64: The average loss.
+//   - error: An error if the input and target arrays do not match the expected dimensions, otherwise nil.
+func (neural *Neural) Evaluate(inputArray, targetArray [][]float64) (float64, error) {
+	if err := neural.validate(inputArray, targetArray); err != nil {
+		return 0, err
+	}
+
+	loss, _, err := neural.evaluate(inputArray, targetArray, nil)
+	return loss, err
+}
+
+// evaluate measures the loss and the metrics of the network on already validated samples.
+func (neural *Neural) evaluate(inputArray, targetArray [][]float64, metrics []Metric) (float64, map[string]float64, error) {
+	totals := newTotals(metrics)
+	for start := 0; start < len(inputArray); start += evaluateBatchSize {
+		end := start + evaluateBatchSize
+		if end > len(inputArray) {
+			end = len(inputArray)
+		}
+
+		pass, err := neural.forward(matrix.NewFromColumns(inputArray[start:end]), false, nil, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+		targets := matrix.NewFromColumns(targetArray[start:end])
+		loss, err := neural.loss().Loss(pass.outputs(), targets)
+		if err != nil {
+			return 0, nil, err
+		}
+		if err := totals.add(pass.outputs(), targets, loss+neural.penalty()); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	loss, values := totals.average()
+	return loss, values, nil
+}
+
 // shuffleArrays shuffles the input and target arrays while maintaining their correspondence.
 // Both inputArray and targetArray are 2D slices ([][]float64).
-func shuffleArrays(inputArray, targetArray [][]float64) ([][]float64, [][]float64) {
+func shuffleArrays(rng *rand.Rand, inputArray, targetArray [][]float64) ([][]float64, [][]float64) {
 	// Create a slice of indices
 	indices := make([]int, len(inputArray))
 	for i := range indices {
@@ -50,7 +387,7 @@ func shuffleArrays(inputArray, targetArray [][]float64) ([][]float64, [][]float6
 	}
 
 	// Shuffle the indices
-	rand.Shuffle(len(indices), func(i, j int) {
+	rng.Shuffle(len(indices), func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
 
@@ -73,8 +410,11 @@ func shuffleArrays(inputArray, targetArray [][]float64) ([][]float64, [][]float6
 // - targetArray: A 2D slice of float64 representing the target data.
 //
 // Returns:
-// - error: An error if the input and target arrays do not match the expected dimensions, otherwise nil.
+// - error: ErrNoLayers if the network has no layers, an error if the input and target arrays do not match the expected dimensions, otherwise nil.
 func (neural *Neural) validate(inputArray, targetArray [][]float64) error {
+	if len(neural.Layers) == 0 {
+		return errors.ErrNoLayers
+	}
 
 	if len(inputArray) == 0 || len(targetArray) == 0 {
 		return errors.ErrEmptyInputOutput
@@ -0,0 +1,129 @@
+package neural
+
+import "neuraln/matrix"
+
+// buffer names one of the matrices a workspace keeps for every layer.
+type buffer int
+
+const (
+	// weightedBuffer holds the weighted sums of the layer.
+	weightedBuffer buffer = iota
+	// maskBuffer holds the dropout mask of the layer.
+	maskBuffer
+	// droppedBuffer holds the outputs of the layer after dropout.
+	droppedBuffer
+	// weightsBuffer and biasBuffer hold the gradients of the layer's parameters.
+	weightsBuffer
+	biasBuffer
+	// previousBuffer holds the gradient propagated to the previous layer.
+	previousBuffer
+	buffers
+)
+
+// workspace keeps the matrices of a training step and hands them out again in the
+// next step, so that Train allocates its large intermediate matrices once instead
+// of in every step. A matrix is replaced when it is asked for in another shape,
+// e.g. for the smaller last batch of an epoch. The matrices still hold the values
+// of the previous step, so callers overwrite them entirely.
+//
+// Every goroutine needs a workspace of its own, and the matrices a step returns,
+// such as its gradients, are only valid until the next step. A nil workspace
+// allocates every matrix anew, which is what inference and Gradients use.
+type workspace struct {
+	layers          [][buffers]*matrix.Matrix
+	inputs, targets *matrix.Matrix
+	// accumulated holds the gradients of a step that spans several micro-batches.
+	accumulated *Gradients
+	// shards holds the workspaces of the goroutines of shardedGradients.
+	shards []*workspace
+}
+
+// matrix returns the rows x cols matrix kept as buffer b of the given layer.
+func (w *workspace) matrix(layer int, b buffer, rows, cols int) *matrix.Matrix {
+	if w == nil {
+		return matrix.New(rows, cols)
+	}
+	for len(w.layers) <= layer {
+		w.layers = append(w.layers, [buffers]*matrix.Matrix{})
+	}
+	return reuse(&w.layers[layer][b], rows, cols)
+}
+
+// columns returns the samples as the columns of a matrix, reusing the inputs or,
+// if targets is set, the targets matrix of the workspace.
+func (w *workspace) columns(samples [][]float64, targets bool) *matrix.Matrix {
+	if w == nil {
+		return matrix.NewFromColumns(samples)
+	}
+
+	rows := 0
+	if len(samples) > 0 {
+		rows = len(samples[0])
+	}
+	slot := &w.inputs
+	if targets {
+		slot = &w.targets
+	}
+	m := reuse(slot, rows, len(samples))
+	for j, sample := range samples {
+		for i, v := range sample {
+			m.Data[i*m.Col+j] = v
+		}
+	}
+	return m
+}
+
+// shard returns the workspace of the k-th goroutine of shardedGradients. It must
+// be called before the goroutines start.
+func (w *workspace) shard(k int) *workspace {
+	if w == nil {
+		return nil
+	}
+	for len(w.shards) <= k {
+		w.shards = append(w.shards, &workspace{})
+	}
+	return w.shards[k]
+}
+
+// accumulate copies grads into gradients owned by the workspace and returns them,
+// so that the following micro-batches of the step can add their gradients to them
+// while reusing the buffers grads lives in.
+func (w *workspace) accumulate(grads *Gradients) *Gradients {
+	if w == nil {
+		return grads
+	}
+	if w.accumulated == nil || len(w.accumulated.Layers) != len(grads.Layers) {
+		w.accumulated = &Gradients{Layers: make([]LayerGradients, len(grads.Layers))}
+	}
+
+	for i, layer := range grads.Layers {
+		ours := &w.accumulated.Layers[i]
+		ours.Weights = copyReusing(ours.Weights, layer.Weights)
+		ours.Bias = copyReusing(ours.Bias, layer.Bias)
+		ours.Gamma = copyReusing(ours.Gamma, layer.Gamma)
+		ours.Beta = copyReusing(ours.Beta, layer.Beta)
+	}
+	w.accumulated.Loss = grads.Loss
+	w.accumulated.statistics = append(w.accumulated.statistics[:0], grads.statistics...)
+	return w.accumulated
+}
+
+// reuse returns the matrix in slot if it has the given shape, otherwise it stores
+// a new one there.
+func reuse(slot **matrix.Matrix, rows, cols int) *matrix.Matrix {
+	if *slot == nil || (*slot).Row != rows || (*slot).Col != cols {
+		*slot = matrix.New(rows, cols)
+	}
+	return *slot
+}
+
+// copyReusing copies src into dst, replacing dst if its shape differs, and returns
+// the copy. A nil src returns nil.
+func copyReusing(dst, src *matrix.Matrix) *matrix.Matrix {
+	if src == nil {
+		return nil
+	}
+	dst = reuse(&dst, src.Row, src.Col)
+	src.CopyInto(dst)
+	return dst
+}